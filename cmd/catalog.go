@@ -1,11 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	htmltemplate "html/template"
+	"io"
 	"log"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
 
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
 	"github.com/blaxel-ai/mcp-hub/internal/hub"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
@@ -19,13 +30,27 @@ var catalogCmd = &cobra.Command{
 }
 
 func init() {
-	catalogCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files")
+	catalogCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
 	catalogCmd.Flags().BoolVarP(&push, "push", "p", false, "Push the images to the registry")
 	catalogCmd.Flags().StringVarP(&registry, "registry", "r", "ghcr.io/blaxel-ai/hub", "The registry to push the images to")
 	catalogCmd.Flags().StringVarP(&mcp, "mcp", "m", "", "The MCP to import, if not provided")
+	catalogCmd.Flags().StringVar(&branch, "branch", "", "Override the branch of the MCP repository")
+	catalogCmd.Flags().StringVar(&commit, "commit", "", "Override the commit of the MCP repository, takes precedence over the branch head")
 	catalogCmd.Flags().BoolVarP(&skipBuild, "skip-build", "s", true, "Skip building the image")
-	catalogCmd.Flags().StringVarP(&tag, "tag", "t", "latest", "The tag to use for the image")
+	catalogCmd.Flags().StringVarP(&tag, "tag", "t", "", "The tag to use for the image, defaults to the VERSION file of the config, git describe or latest")
 	catalogCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug mode, will not save the catalog")
+	catalogCmd.Flags().StringVar(&proxy, "proxy", "", "The proxy to use for clone and build, defaults to the HTTP_PROXY and HTTPS_PROXY environment variables")
+	catalogCmd.Flags().IntVar(&maxLongDescription, "max-long-description", 2000, "The maximum length of a long description, 0 to disable the check")
+	catalogCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	catalogCmd.Flags().StringVar(&vocabularyPath, "vocabulary", "", "The file listing the allowed tags and categories, not checked when empty")
+	catalogCmd.Flags().BoolVar(&inlineIcons, "inline-icons", false, "Download the remote icons and store them in the catalog as data URIs")
+	catalogCmd.Flags().BoolVar(&sanitize, "sanitize", false, "Strip scripts and disallowed HTML elements from the descriptions of the catalog")
+	catalogCmd.Flags().StringVar(&visibility, "visibility", hub.VisibilityPublic, "The visibility of the catalog, public leaves out the internal repositories and fields")
+	catalogCmd.Flags().BoolVar(&includeDisabled, "include-disabled", false, "Include the disabled repositories in the catalog")
+	catalogCmd.Flags().BoolVar(&includeDeprecated, "include-deprecated", false, "Include the deprecated repositories in the CSV, their own catalog always has a deprecation notice")
+	catalogCmd.Flags().StringVar(&outputTemplate, "output-template", "", "Render the catalog of the MCP with this Go template file instead of printing its JSON, .html files use html/template")
+	catalogCmd.Flags().BoolVar(&catalogWatch, "watch", false, "Print the catalog again on every change of the config directory or of the local path of the MCP")
+	catalogCmd.Flags().StringVar(&catalogFormat, "format", "json", "The output format, json for the catalog of a MCP or csv for a row per repository")
 	rootCmd.AddCommand(catalogCmd)
 }
 
@@ -38,6 +63,19 @@ func runCatalog(cmd *cobra.Command, args []string) {
 	if configPath == "" {
 		configPath = "hub"
 	}
+	switch catalogFormat {
+	case "json":
+	case "csv":
+		// Every field of the CSV comes from the config, the repositories are not cloned
+		if err := writeCatalogCSV(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate catalog: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	default:
+		log.Printf("Unknown format %s, expected json or csv", catalogFormat)
+		os.Exit(1)
+	}
 	if mcp == "" {
 		log.Printf("MCP is required")
 		os.Exit(1)
@@ -47,17 +85,145 @@ func runCatalog(cmd *cobra.Command, args []string) {
 	debug = true
 	skipBuild = true
 
-	hub := hub.Hub{}
-	handleError("read config file", hub.Read(configPath))
-	handleError("validate config file", hub.ValidateWithDefaultValues())
+	var tmpl catalogTemplate
+	if outputTemplate != "" {
+		// Parse first so a broken template fails before the repository is cloned
+		var err error
+		if tmpl, err = parseOutputTemplate(outputTemplate); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to parse output template: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	explicitTag := cmd.Flags().Changed("tag")
+	printCatalog := func() error {
+		if tmpl != nil {
+			artifact, err := loadArtifact(mcp, explicitTag)
+			if err != nil {
+				return err
+			}
+			return tmpl.Execute(os.Stdout, artifact)
+		}
+		output, err := generateCatalog(mcp, explicitTag)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
 
-	repository := hub.Repositories[mcp]
-	c, err := processRepository(mcp, repository)
-	if err != nil {
-		log.Printf("Failed to process repository %s: %v", mcp, err)
+	if catalogWatch {
+		handleError("watch catalog", watchCatalog(context.Background(), printCatalog))
+		return
+	}
+	// Errors go to stderr so stdout only ever carries the catalog JSON
+	if err := printCatalog(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate catalog for %s: %v\n", mcp, err)
 		os.Exit(1)
 	}
-	artifact := c.Artifacts[0]
-	json, _ := json.MarshalIndent(artifact, "", "  ")
-	fmt.Printf("%s", string(json))
+}
+
+// catalogTemplate is a parsed text or html template
+type catalogTemplate interface {
+	Execute(w io.Writer, data any) error
+}
+
+// parseOutputTemplate parses the template rendering the catalog artifact, html/template escapes the .html files
+func parseOutputTemplate(path string) (catalogTemplate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	funcs := map[string]any{
+		"join": strings.Join,
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}
+	name := filepath.Base(path)
+	switch filepath.Ext(path) {
+	case ".html", ".htm":
+		return htmltemplate.New(name).Funcs(funcs).Parse(string(content))
+	default:
+		return template.New(name).Funcs(funcs).Parse(string(content))
+	}
+}
+
+func generateCatalog(name string, explicitTag bool) ([]byte, error) {
+	artifact, err := loadArtifact(name, explicitTag)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(artifact, "", "  ")
+}
+
+// loadArtifact resolves the catalog artifact of a repository without building nor saving it
+func loadArtifact(name string, explicitTag bool) (*catalog.Artifact, error) {
+	h, err := readHub()
+	if err != nil {
+		return nil, err
+	}
+	if tag, err = resolveTag(explicitTag, h.Path); err != nil {
+		return nil, err
+	}
+
+	repository := h.Repositories[name]
+	if repository == nil {
+		return nil, fmt.Errorf("repository %s not found", name)
+	}
+	if !repository.InCatalog(includeDisabled) {
+		return nil, fmt.Errorf("repository %s is disabled, use --include-disabled to generate its catalog", name)
+	}
+	if visibility == hub.VisibilityPublic && repository.Visibility == hub.VisibilityInternal {
+		return nil, fmt.Errorf("repository %s is internal, use --visibility internal to generate its catalog", name)
+	}
+	if err := repository.OverrideRef(branch, commit); err != nil {
+		return nil, err
+	}
+	c, err := processRepository(name, repository, &importResult{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Artifacts) == 0 {
+		return nil, fmt.Errorf("no artifact generated")
+	}
+	return &c.Artifacts[0], nil
+}
+
+// writeCatalogCSV writes a row per repository of the config, or only --mcp when set, sorted by name
+func writeCatalogCSV(out io.Writer) error {
+	h, err := readHub()
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"name", "displayName", "description", "tags", "categories", "enterprise", "comingSoon"}); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		repository := h.Repositories[name]
+		if (mcp != "" && mcp != name) || !repository.InCatalog(includeDisabled) {
+			continue
+		}
+		if visibility == hub.VisibilityPublic && repository.Visibility == hub.VisibilityInternal {
+			continue
+		}
+		if repository.Deprecated && !includeDeprecated {
+			continue
+		}
+		err := w.Write([]string{
+			name,
+			repository.DisplayName,
+			repository.Description,
+			strings.Join(repository.Tags, ";"),
+			strings.Join(repository.Categories, ";"),
+			strconv.FormatBool(repository.Enterprise),
+			strconv.FormatBool(repository.ComingSoon),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
 }
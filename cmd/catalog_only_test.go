@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+)
+
+func TestImportCatalogOnly(t *testing.T) {
+	url, commits := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"smithery.yaml": smitheryFixture}})
+	dir := testConfig(t)
+	writeFiles(t, dir, map[string]string{"hub/exa.yaml": fmt.Sprintf(lockedConfig, url, "main", "")})
+	dockerLog := fakeTool(t, "docker", "exit 1")
+
+	result := runCLI(t, dir, nil, "import", "-c", "hub", "--catalog-only", "--push", "--tag", "v1", "--registry", "registry.test")
+	if result.code != 0 {
+		t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+	}
+	if calls := toolCalls(t, dockerLog); calls != nil {
+		t.Errorf("docker called %q, want no build nor push", calls)
+	}
+	for name, commit := range map[string]string{"brave": "", "exa": commits["main"]} {
+		content, err := os.ReadFile(filepath.Join(dir, catalog.CatalogDir, name+".json"))
+		if err != nil {
+			t.Fatalf("catalog of %s not written: %v", name, err)
+		}
+		var artifact catalog.Artifact
+		if err := json.Unmarshal(content, &artifact); err != nil {
+			t.Fatal(err)
+		}
+		if artifact.Image != "registry.test/"+name+":v1" || artifact.Source == nil || artifact.Source.Commit != commit {
+			t.Errorf("catalog of %s: image %s, source %+v", name, artifact.Image, artifact.Source)
+		}
+	}
+}
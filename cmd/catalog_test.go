@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+// testConfig writes a hub config of the brave MCP built from a local source and returns its directory
+func testConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"src/Dockerfile": "FROM node:22-alpine\n",
+		"hub/brave.yaml": fmt.Sprintf(braveConfig, filepath.Join(dir, "src")),
+	})
+	return dir
+}
+
+func TestCatalogExitCode(t *testing.T) {
+	dir := testConfig(t)
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		wantJSON bool
+		stderr   string
+	}{
+		{name: "success", args: []string{"catalog", "-c", "hub", "-m", "brave", "--tag", "v1"}, wantJSON: true},
+		{name: "unknown MCP", args: []string{"catalog", "-c", "hub", "-m", "notion", "--tag", "v1"}, code: 1, stderr: "Failed to generate catalog for notion: repository notion not found"},
+		{name: "invalid config", args: []string{"catalog", "-c", "missing", "-m", "brave", "--tag", "v1"}, code: 1, stderr: "Failed to generate catalog for brave"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCLI(t, dir, nil, tt.args...)
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			if !strings.Contains(result.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", result.stderr, tt.stderr)
+			}
+			if !tt.wantJSON {
+				if result.stdout != "" {
+					t.Errorf("stdout = %q, want nothing on failure", result.stdout)
+				}
+				return
+			}
+			var artifact catalog.Artifact
+			if err := json.Unmarshal([]byte(result.stdout), &artifact); err != nil {
+				t.Fatalf("stdout is not a catalog: %v\n%s", err, result.stdout)
+			}
+			if artifact.Name != "brave" || artifact.Entrypoint.Command != "node" {
+				t.Errorf("got artifact %+v", artifact)
+			}
+		})
+	}
+}
+
+func TestCatalogIncludeDisabled(t *testing.T) {
+	dir := testConfig(t)
+	writeFiles(t, dir, map[string]string{
+		"hub/legacy.yaml": fmt.Sprintf(braveConfig, filepath.Join(dir, "src")) + "disabled: true\n",
+	})
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		rows   []string
+		stderr string
+	}{
+		{name: "csv excludes disabled", args: []string{"--format", "csv"}, rows: []string{"brave"}},
+		{name: "csv includes disabled", args: []string{"--format", "csv", "--include-disabled"}, rows: []string{"brave", "legacy"}},
+		{name: "json refuses disabled", args: []string{"-m", "legacy"}, code: 1, stderr: "repository legacy is disabled, use --include-disabled"},
+		{name: "json includes disabled", args: []string{"-m", "legacy", "--include-disabled"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCLI(t, dir, nil, append([]string{"catalog", "-c", "hub", "--tag", "v1"}, tt.args...)...)
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			if !strings.Contains(result.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", result.stderr, tt.stderr)
+			}
+			if tt.rows == nil {
+				return
+			}
+			var names []string
+			for _, line := range strings.Split(strings.TrimSpace(result.stdout), "\n")[1:] {
+				names = append(names, strings.SplitN(line, ",", 2)[0])
+			}
+			if strings.Join(names, " ") != strings.Join(tt.rows, " ") {
+				t.Errorf("rows %v, want %v", names, tt.rows)
+			}
+		})
+	}
+}
+
+func TestWriteCatalogCSV(t *testing.T) {
+	dir := testConfig(t)
+	writeFiles(t, dir, map[string]string{
+		"hub/slack.yaml": strings.Replace(fmt.Sprintf(braveConfig, filepath.Join(dir, "src")),
+			"description: Search the web using Brave's search engine.", `description: 'Send "messages", files'`, 1) +
+			"enterprise: true\ntags:\n  - chat\n  - messaging\n",
+	})
+	setFlag(t, &configPath, filepath.Join(dir, "hub"))
+	setFlag(t, &mcp, "")
+	setFlag(t, &visibility, hub.VisibilityPublic)
+
+	var out strings.Builder
+	if err := writeCatalogCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := `name,displayName,description,tags,categories,enterprise,comingSoon
+brave,Brave Search,Search the web using Brave's search engine.,,search,false,false
+slack,Brave Search,"Send ""messages"", files",chat;messaging,search,true,false
+`
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestCatalogOutputTemplate(t *testing.T) {
+	dir := testConfig(t)
+	writeFiles(t, dir, map[string]string{
+		"row.tmpl":    "| {{.Name}} | {{.DisplayName}} | {{join .Categories \", \"}} | {{.Entrypoint.Command}} |\n",
+		"card.html":   "<h1>{{.Description}}</h1>",
+		"broken.tmpl": "{{.Name",
+	})
+	tests := []struct {
+		name     string
+		template string
+		code     int
+		stdout   string
+		stderr   string
+	}{
+		{name: "text template", template: "row.tmpl", stdout: "| brave | Brave Search | search | node |\n"},
+		{name: "html template", template: "card.html", stdout: "<h1>Search the web using Brave&#39;s search engine.</h1>"},
+		{name: "broken template", template: "broken.tmpl", code: 1, stderr: "Failed to parse output template: template: broken.tmpl:1: unclosed action"},
+		{name: "missing template", template: "missing.tmpl", code: 1, stderr: "Failed to parse output template: open missing.tmpl: no such file or directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCLI(t, dir, nil, "catalog", "-c", "hub", "-m", "brave", "--tag", "v1", "--output-template", tt.template)
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			if result.stdout != tt.stdout {
+				t.Errorf("stdout = %q, want %q", result.stdout, tt.stdout)
+			}
+			if !strings.Contains(result.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", result.stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestCatalogDeprecated(t *testing.T) {
+	dir := testConfig(t)
+	config := fmt.Sprintf(braveConfig, filepath.Join(dir, "src"))
+	writeFiles(t, dir, map[string]string{
+		"hub/brave-legacy.yaml": strings.Replace(config, "Brave Search", "Brave Legacy", 1) + "deprecated: true\nreplacedBy: brave\n",
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		for args, want := range map[string]string{"": "brave", "--include-deprecated": "brave brave-legacy"} {
+			result := runCLI(t, dir, nil, strings.Fields("catalog -c hub --format csv "+args)...)
+			if result.code != 0 {
+				t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+			}
+			var names []string
+			for _, line := range strings.Split(strings.TrimSpace(result.stdout), "\n")[1:] {
+				names = append(names, strings.SplitN(line, ",", 2)[0])
+			}
+			if strings.Join(names, " ") != want {
+				t.Errorf("%q: rows %v, want %s", args, names, want)
+			}
+		}
+	})
+
+	t.Run("own catalog", func(t *testing.T) {
+		result := runCLI(t, dir, nil, "catalog", "-c", "hub", "-m", "brave-legacy", "--tag", "v1")
+		if result.code != 0 {
+			t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+		}
+		var artifact catalog.Artifact
+		if err := json.Unmarshal([]byte(result.stdout), &artifact); err != nil {
+			t.Fatalf("stdout is not a catalog: %v\n%s", err, result.stdout)
+		}
+		if !artifact.Deprecated || artifact.ReplacedBy != "brave" || artifact.DeprecationNotice != "Brave Legacy is deprecated, use brave instead" {
+			t.Errorf("got deprecated %t, replaced by %q, notice %q", artifact.Deprecated, artifact.ReplacedBy, artifact.DeprecationNotice)
+		}
+	})
+
+	t.Run("unknown replacement", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"hub/brave-legacy.yaml": strings.Replace(config, "Brave Search", "Brave Legacy", 1) + "deprecated: true\nreplacedBy: brave-v3\n",
+		})
+		result := runCLI(t, dir, nil, "catalog", "-c", "hub", "-m", "brave-legacy", "--tag", "v1")
+		if result.code != 1 || !strings.Contains(result.stderr, "repository brave-legacy: field ReplacedBy is invalid: unknown repository brave-v3") {
+			t.Errorf("exit code %d, stderr:\n%s", result.code, result.stderr)
+		}
+	})
+}
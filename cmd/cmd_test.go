@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cliArgsEnv is set to the JSON arguments of the CLI when the test binary is run as the CLI by runCLI
+const cliArgsEnv = "MCP_HUB_TEST_ARGS"
+
+func TestMain(m *testing.M) {
+	if encoded := os.Getenv(cliArgsEnv); encoded != "" {
+		var args []string
+		if err := json.Unmarshal([]byte(encoded), &args); err != nil {
+			panic(err)
+		}
+		rootCmd.SetArgs(args)
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// cliResult is the output and exit code of a CLI run
+type cliResult struct {
+	stdout string
+	stderr string
+	code   int
+}
+
+// runCLI runs the CLI with args in a new process of the test binary, in dir, as the commands exit on errors
+func runCLI(t *testing.T, dir string, env []string, args ...string) cliResult {
+	t.Helper()
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(os.Args[0])
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), cliArgsEnv+"="+string(encoded)), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatal(err)
+	}
+	return cliResult{stdout: stdout.String(), stderr: stderr.String(), code: cmd.ProcessState.ExitCode()}
+}
+
+// braveConfig is the config of a MCP built from a local path with an inline smithery config, so it never clones
+const braveConfig = `path: %s
+dockerfile: Dockerfile
+displayName: Brave Search
+license: MIT
+url: https://brave.com/search/api
+icon: https://brave.com/logo.svg
+description: Search the web using Brave's search engine.
+longDescription: Search the web using Brave's search engine.
+integration: brave-search
+secrets:
+  - braveApiKey
+categories:
+  - search
+smithery:
+  startCommand:
+    type: stdio
+    configSchema:
+      type: object
+      required:
+        - braveApiKey
+      properties:
+        braveApiKey:
+          type: string
+          description: The API key of Brave search.
+    commandFunction: |-
+      config=>({command:'node',args:['dist/index.js'],env:{BRAVE_API_KEY:config.braveApiKey}})
+`
+
+// writeFiles writes the files of a map of relative path to content under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// setFlag sets the global of a flag for the duration of the test
+func setFlag[T any](t *testing.T, flag *T, value T) {
+	t.Helper()
+	prev := *flag
+	*flag = value
+	t.Cleanup(func() { *flag = prev })
+}
+
+// fakeTool installs an executable name in front of the PATH running script, it logs its arguments, one call per
+// line, to the returned file
+func fakeTool(t *testing.T, name string, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, name+".log")
+	content := "#!/bin/sh\necho \"$@\" >> " + logPath + "\n" + script + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logPath
+}
+
+// toolCalls returns the arguments of every call of a fake tool
+func toolCalls(t *testing.T, logPath string) []string {
+	t.Helper()
+	content, err := os.ReadFile(logPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
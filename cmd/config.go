@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/spf13/cobra"
+)
+
+var dumpFormat string
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the hub configuration",
+}
+
+var configDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the resolved hub configuration",
+	Long:  `dump is a CLI tool to print the hub configuration as used by the build, with the default values and templates applied`,
+	Run:   runConfigDump,
+}
+
+func init() {
+	configDumpCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	configDumpCmd.Flags().StringVarP(&mcp, "mcp", "m", "", "The MCP to print, if not provided, all MCPs will be printed")
+	configDumpCmd.Flags().StringVar(&dumpFormat, "format", "yaml", "The output format, yaml or json")
+	configDumpCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	configCmd.AddCommand(configDumpCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigDump(cmd *cobra.Command, args []string) {
+	if configPath == "" {
+		configPath = "hub"
+	}
+
+	h, err := readHub()
+	handleError("load config", err)
+	if mcp != "" {
+		repository := h.Repositories[mcp]
+		if repository == nil {
+			log.Printf("Repository %s not found", mcp)
+			os.Exit(1)
+		}
+		h = &hub.Hub{Repositories: map[string]*hub.Repository{mcp: repository}, Path: h.Path}
+	}
+	handleError("write config", h.Write(os.Stdout, dumpFormat))
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConfigDump(t *testing.T) {
+	dir := testConfig(t)
+
+	// packageManager and smitheryPath are left empty in the source
+	result := runCLI(t, dir, nil, "config", "dump", "-c", "hub", "-m", "brave")
+	if result.code != 0 {
+		t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+	}
+	for _, want := range []string{"\n    packageManager: apk\n", "\n    smitheryPath: smithery.yaml\n"} {
+		if !strings.Contains(result.stdout, want) {
+			t.Errorf("dump is missing %q:\n%s", want, result.stdout)
+		}
+	}
+
+	result = runCLI(t, dir, nil, "config", "dump", "-c", "hub", "--format", "json")
+	if result.code != 0 {
+		t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+	}
+	var dump struct {
+		Repositories map[string]map[string]any `json:"repositories"`
+	}
+	if err := json.Unmarshal([]byte(result.stdout), &dump); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, result.stdout)
+	}
+	if got := dump.Repositories["brave"]["packageManager"]; got != "apk" {
+		t.Errorf("packageManager = %v, want the default apk", got)
+	}
+
+	result = runCLI(t, dir, nil, "config", "dump", "-c", "hub", "-m", "notion")
+	if result.code != 1 || !strings.Contains(result.stderr, "Repository notion not found") {
+		t.Errorf("exit code %d, stderr %q, want the unknown MCP", result.code, result.stderr)
+	}
+}
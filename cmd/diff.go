@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffRemote string
+	diffDir    string
+	diffJSON   bool
+)
+
+var catalogDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the local catalog with the published one",
+	Long:  `diff is a CLI tool to list the MCPs added, removed and changed by publishing the catalog generated locally, e.g. by import --catalog-only`,
+	Run:   runCatalogDiff,
+}
+
+func init() {
+	catalogDiffCmd.Flags().StringVar(&diffRemote, "remote", "", "The URL of the control plane, defaults to BL_API_URL, authenticated with BL_ADMIN_USERNAME and BL_ADMIN_PASSWORD")
+	catalogDiffCmd.Flags().StringVar(&diffDir, "dir", catalog.CatalogDir, "The directory of the local catalog")
+	catalogDiffCmd.Flags().BoolVar(&diffJSON, "json", false, "Print the diff as JSON")
+	catalogCmd.AddCommand(catalogDiffCmd)
+}
+
+func runCatalogDiff(cmd *cobra.Command, args []string) {
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: No .env file found or error loading it: %v", err)
+	}
+
+	if diffRemote == "" {
+		diffRemote = os.Getenv("BL_API_URL")
+	}
+	if diffRemote == "" {
+		log.Printf("Remote is required, set --remote or BL_API_URL")
+		os.Exit(1)
+	}
+
+	local, err := catalog.ReadArtifacts(diffDir)
+	handleError("read local catalog", err)
+	remote, err := catalog.FetchArtifacts(diffRemote, os.Getenv("BL_ADMIN_USERNAME"), os.Getenv("BL_ADMIN_PASSWORD"))
+	handleError("fetch published catalog", err)
+
+	diff := catalog.Diff(local, remote)
+	if diffJSON {
+		output, err := json.MarshalIndent(diff, "", "  ")
+		handleError("encode diff", err)
+		fmt.Println(string(output))
+		return
+	}
+	writeCatalogDiff(os.Stdout, diff)
+}
+
+// writeCatalogDiff writes a line per added (+), removed (-) and changed (~) MCP
+func writeCatalogDiff(out io.Writer, diff *catalog.CatalogDiff) {
+	if diff.Empty() {
+		fmt.Fprintln(out, "No changes")
+		return
+	}
+	for _, name := range diff.Added {
+		fmt.Fprintf(out, "+ %s\n", name)
+	}
+	for _, name := range diff.Removed {
+		fmt.Fprintf(out, "- %s\n", name)
+	}
+	for _, changed := range diff.Changed {
+		fmt.Fprintf(out, "~ %s: %s\n", changed.Name, strings.Join(changed.Fields, ", "))
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+)
+
+func TestCatalogDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "admin" || password != "secret" {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"name": "brave", "image": "ghcr.io/hub/brave:v1"}, {"name": "notion", "image": "ghcr.io/hub/notion:v1"}]`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"catalog/brave.json": `{"name": "brave", "image": "ghcr.io/hub/brave:v2"}`,
+		"catalog/exa.json":   `{"name": "exa", "image": "ghcr.io/hub/exa:v1"}`,
+	})
+	env := []string{"BL_API_URL=" + server.URL, "BL_ADMIN_USERNAME=admin", "BL_ADMIN_PASSWORD=secret"}
+
+	t.Run("text", func(t *testing.T) {
+		result := runCLI(t, dir, env, "catalog", "diff")
+		if result.code != 0 {
+			t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+		}
+		if want := "+ exa\n- notion\n~ brave: image\n"; result.stdout != want {
+			t.Errorf("got\n%s\nwant\n%s", result.stdout, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		result := runCLI(t, dir, env, "catalog", "diff", "--remote", server.URL, "--json")
+		if result.code != 0 {
+			t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+		}
+		var got catalog.CatalogDiff
+		if err := json.Unmarshal([]byte(result.stdout), &got); err != nil {
+			t.Fatalf("stdout is not a diff: %v\n%s", err, result.stdout)
+		}
+		want := catalog.CatalogDiff{Added: []string{"exa"}, Removed: []string{"notion"}, Changed: []catalog.Changed{{Name: "brave", Fields: []string{"image"}}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got  %+v\nwant %+v", got, want)
+		}
+	})
+
+	t.Run("empty local catalog", func(t *testing.T) {
+		result := runCLI(t, dir, env, "catalog", "diff", "--dir", t.TempDir())
+		if result.code != 0 {
+			t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+		}
+		if want := "- brave\n- notion\n"; result.stdout != want {
+			t.Errorf("got\n%s\nwant\n%s", result.stdout, want)
+		}
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		result := runCLI(t, dir, []string{"BL_ADMIN_USERNAME=admin", "BL_ADMIN_PASSWORD=wrong"}, "catalog", "diff", "--remote", server.URL)
+		if result.code != 1 || result.stdout != "" {
+			t.Errorf("exit code %d, stdout %q, want the failed fetch", result.code, result.stdout)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var envJSON bool
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "List the environment variables of a MCP server",
+	Long:  `env is a CLI tool to list the required and optional environment variables of a MCP server`,
+	Run:   runEnv,
+}
+
+func init() {
+	envCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	envCmd.Flags().StringVarP(&mcp, "mcp", "m", "", "The MCP to list the environment variables of")
+	envCmd.Flags().StringVar(&branch, "branch", "", "Override the branch of the MCP repository")
+	envCmd.Flags().StringVar(&commit, "commit", "", "Override the commit of the MCP repository, takes precedence over the branch head")
+	envCmd.Flags().StringVar(&proxy, "proxy", "", "The proxy to use for clone, defaults to the HTTP_PROXY and HTTPS_PROXY environment variables")
+	envCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	envCmd.Flags().BoolVar(&envJSON, "json", false, "Print the environment variables as JSON")
+	rootCmd.AddCommand(envCmd)
+}
+
+// envVar is an environment variable read by the entrypoint of a MCP server
+type envVar struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret"`
+	Hidden      bool   `json:"hidden,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func runEnv(cmd *cobra.Command, args []string) {
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: No .env file found or error loading it: %v", err)
+	}
+
+	if configPath == "" {
+		configPath = "hub"
+	}
+	if mcp == "" {
+		log.Printf("MCP is required")
+		os.Exit(1)
+	}
+
+	// The environment only depends on the smithery config, nothing is built nor saved
+	debug = true
+	skipBuild = true
+
+	artifact, err := loadArtifact(mcp, false)
+	if err != nil {
+		log.Printf("Failed to load repository %s: %v", mcp, err)
+		os.Exit(1)
+	}
+	vars := envVars(*artifact)
+
+	if envJSON {
+		output, err := json.MarshalIndent(vars, "", "  ")
+		handleError("marshal environment variables", err)
+		fmt.Println(string(output))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tREQUIRED\tSECRET\tDEFAULT\tDESCRIPTION")
+	for _, v := range vars {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%s\t%s\n", v.Name, v.Type, v.Required, v.Secret, v.Default, v.Description)
+	}
+	w.Flush()
+}
+
+// envVars returns the environment variables of the entrypoint of an artifact sorted by name,
+// with the schema of the config or secret they are resolved from
+func envVars(artifact catalog.Artifact) []envVar {
+	var vars []envVar
+	for key, val := range artifact.Entrypoint.Env {
+		v := envVar{Name: key}
+		property := strings.Trim(val, "$")
+		if field, ok := artifact.Form.Secrets[property]; ok {
+			v.Secret = true
+			v.Type, v.Required, v.Default, v.Description = field.Type, field.Required, field.Default, field.Description
+		} else if field, ok := artifact.Form.Config[property]; ok {
+			v.Type, v.Required, v.Default, v.Description = field.Type, field.Required, field.Default, field.Description
+		} else if slices.Contains(artifact.HiddenSecrets, property) {
+			v.Secret = true
+			v.Hidden = true
+		} else {
+			// Not resolved from the config, the entrypoint sets a fixed value
+			v.Default = val
+		}
+		vars = append(vars, v)
+	}
+	slices.SortFunc(vars, func(a, b envVar) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return vars
+}
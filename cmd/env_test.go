@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// searchConfig is the config of a MCP reading a secret, an optional config and a fixed value
+const searchConfig = `path: %s
+displayName: Search
+license: MIT
+url: https://search.example.com
+icon: https://search.example.com/logo.svg
+description: Search the web.
+longDescription: Search the web.
+integration: brave-search
+secrets:
+  - apiKey
+smithery:
+  startCommand:
+    type: stdio
+    configSchema:
+      type: object
+      required:
+        - apiKey
+      properties:
+        apiKey:
+          type: string
+          description: The API key.
+        region:
+          type: string
+          description: The region of the results.
+    commandFunction: |-
+      config=>({command:'node',args:['index.js'],env:{API_KEY:config.apiKey,REGION:config.region,LOG_FORMAT:'json'}})
+`
+
+func TestEnv(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"src/Dockerfile":  "FROM node:22-alpine\n",
+		"hub/search.yaml": fmt.Sprintf(searchConfig, filepath.Join(dir, "src")),
+	})
+
+	result := runCLI(t, dir, nil, "env", "-c", "hub", "-m", "search", "--json")
+	if result.code != 0 {
+		t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+	}
+	var vars []envVar
+	if err := json.Unmarshal([]byte(result.stdout), &vars); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, result.stdout)
+	}
+	want := []envVar{
+		{Name: "API_KEY", Type: "string", Required: true, Secret: true, Description: "The API key."},
+		{Name: "LOG_FORMAT", Default: "json"},
+		{Name: "REGION", Type: "string", Description: "The region of the results."},
+	}
+	if !slices.Equal(vars, want) {
+		t.Errorf("got  %+v\nwant %+v", vars, want)
+	}
+
+	result = runCLI(t, dir, nil, "env", "-c", "hub", "-m", "search")
+	lines := strings.Split(strings.TrimSpace(result.stdout), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "NAME") || !strings.HasPrefix(lines[1], "API_KEY") {
+		t.Errorf("got table\n%s", result.stdout)
+	}
+}
+
+func TestImportCheckEnv(t *testing.T) {
+	dir := testConfig(t)
+	writeFiles(t, dir, map[string]string{"hub/search.yaml": fmt.Sprintf(searchConfig, filepath.Join(dir, "src"))})
+	args := []string{"import", "-c", "hub", "--skip-build", "-d", "--tag", "v1", "--check-env", "--report-out", "report.json"}
+
+	result := runCLI(t, dir, nil, args...)
+	if result.code != 1 {
+		t.Fatalf("exit code %d, want 1, stderr:\n%s", result.code, result.stderr)
+	}
+	want := "Environment variable BRAVE_API_KEY is not set and is required for the MCP brave\n" +
+		"Environment variable API_KEY is not set and is required for the MCP search"
+	if !strings.Contains(result.stderr, want) {
+		t.Errorf("stderr = %q, want every missing variable:\n%s", result.stderr, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.json")); !os.IsNotExist(err) {
+		t.Error("the import ran after the failed preflight")
+	}
+
+	result = runCLI(t, dir, []string{"BRAVE_API_KEY=secret", "API_KEY=secret"}, args...)
+	if result.code != 0 {
+		t.Errorf("exit code %d with the environment set, stderr:\n%s", result.code, result.stderr)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	huberrors "github.com/blaxel-ai/mcp-hub/internal/errors"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+func TestProcessRepositoryErrors(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	setFlag(t, &tag, "v1")
+	setFlag(t, &debug, true)
+	setFlag(t, &registry, "registry.test")
+	local := filepath.Join(dir, "servers", "brave")
+	writeFiles(t, local, map[string]string{"Dockerfile": "FROM node:22-alpine\n", "smithery.yaml": smitheryFixture})
+
+	tests := []struct {
+		name       string
+		repository *hub.Repository
+		push       bool
+		docker     string
+		check      func(err error) bool
+	}{
+		{
+			name:       "clone",
+			repository: &hub.Repository{Repository: "file://localhost" + filepath.Join(dir, "missing"), Branch: "main"},
+			check: func(err error) bool {
+				var cloneErr *huberrors.CloneError
+				return errors.As(err, &cloneErr) && cloneErr.Repository == "brave"
+			},
+		},
+		{
+			name:       "build",
+			repository: &hub.Repository{Path: local, SmitheryPath: "smithery.yaml", Dockerfile: "Dockerfile", PackageManager: hub.PackageManagerAPK},
+			docker:     `case "$1" in build|buildx) exit 1 ;; esac`,
+			check: func(err error) bool {
+				var buildErr *huberrors.BuildError
+				return errors.As(err, &buildErr) && buildErr.Repository == "brave"
+			},
+		},
+		{
+			name:       "push",
+			repository: &hub.Repository{Path: local, SmitheryPath: "smithery.yaml", Dockerfile: "Dockerfile", PackageManager: hub.PackageManagerAPK},
+			push:       true,
+			docker:     `case "$1" in image|manifest|push) exit 1 ;; esac`,
+			check: func(err error) bool {
+				var pushErr *huberrors.PushError
+				return errors.As(err, &pushErr) && pushErr.Repository == "brave" && pushErr.Image == "registry.test/brave:v1"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(t, &push, tt.push)
+			fakeTool(t, "docker", tt.docker)
+			_, err := processRepository("brave", tt.repository, &importResult{Name: "brave"})
+			if err == nil || !tt.check(err) {
+				t.Errorf("err = %v (%T), want a %s error of brave", err, err, tt.name)
+			}
+		})
+	}
+}
+
+func TestReadHubValidationError(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"hub/brave.yaml": "displayName: Brave Search\n"})
+	setFlag(t, &configPath, filepath.Join(dir, "hub"))
+	_, err := readHub()
+	var validationErr *huberrors.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Repository != "brave" {
+		t.Errorf("err = %v, want a validation error of brave", err)
+	}
+}
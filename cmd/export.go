@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat      string
+	exportOut         string
+	includeEnterprise bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the list of MCP servers",
+	Long:  `export is a CLI tool to export the list of available MCP servers for crawlers and LLM agents`,
+	Run:   runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "llms-txt", "The export format, only llms-txt is supported")
+	exportCmd.Flags().StringVarP(&exportOut, "output", "o", "", "The file to write the export to, defaults to stdout")
+	exportCmd.Flags().BoolVar(&includeDisabled, "include-disabled", false, "Include the disabled repositories")
+	exportCmd.Flags().BoolVar(&includeDeprecated, "include-deprecated", false, "Include the deprecated repositories")
+	exportCmd.Flags().BoolVar(&includeEnterprise, "include-enterprise", false, "Include the enterprise repositories")
+	exportCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	if configPath == "" {
+		configPath = "hub"
+	}
+	if exportFormat != "llms-txt" {
+		log.Printf("Unknown format %s, expected llms-txt", exportFormat)
+		os.Exit(1)
+	}
+
+	hub, err := readHub()
+	handleError("load config", err)
+
+	out := io.Writer(os.Stdout)
+	if exportOut != "" {
+		file, err := os.Create(exportOut)
+		handleError("create output file", err)
+		defer file.Close()
+		out = file
+	}
+	handleError("write export", writeLLMsTxt(out, hub))
+}
+
+// writeLLMsTxt writes the repositories listed in the catalog following the llms.txt convention, sorted by name
+func writeLLMsTxt(out io.Writer, h *hub.Hub) error {
+	if _, err := fmt.Fprint(out, "# MCP Hub\n\n> MCP servers available in the hub, with the service they connect to.\n\n## MCP servers\n\n"); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		repository := h.Repositories[name]
+		// The export is public, internal repositories are never listed
+		if !repository.InCatalog(includeDisabled) || (repository.Enterprise && !includeEnterprise) || repository.Visibility == hub.VisibilityInternal {
+			continue
+		}
+		if repository.Deprecated && !includeDeprecated {
+			continue
+		}
+		entry := repository.DisplayName
+		if repository.URL != "" {
+			entry = fmt.Sprintf("[%s](%s)", repository.DisplayName, repository.URL)
+		}
+		if _, err := fmt.Fprintf(out, "- %s: %s\n", entry, repository.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
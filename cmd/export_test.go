@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+func TestWriteLLMsTxt(t *testing.T) {
+	h := &hub.Hub{Repositories: map[string]*hub.Repository{
+		"brave":      {DisplayName: "Brave Search", URL: "https://brave.com/search/api", Description: "Search the web."},
+		"notion":     {DisplayName: "Notion", Description: "Read Notion pages."},
+		"legacy":     {DisplayName: "Legacy", Description: "Disabled.", Disabled: true},
+		"salesforce": {DisplayName: "Salesforce", Description: "Enterprise.", Enterprise: true},
+		"internal":   {DisplayName: "Internal", Description: "Internal.", Visibility: hub.VisibilityInternal},
+		"old-search": {DisplayName: "Old Search", Description: "Deprecated.", Deprecated: true},
+	}}
+	header := "# MCP Hub\n\n> MCP servers available in the hub, with the service they connect to.\n\n## MCP servers\n\n"
+	tests := []struct {
+		name              string
+		includeDisabled   bool
+		includeEnterprise bool
+		want              string
+	}{
+		{
+			name: "enabled repositories",
+			want: header + "- [Brave Search](https://brave.com/search/api): Search the web.\n- Notion: Read Notion pages.\n",
+		},
+		{
+			name:              "disabled and enterprise included",
+			includeDisabled:   true,
+			includeEnterprise: true,
+			want:              header + "- [Brave Search](https://brave.com/search/api): Search the web.\n- Legacy: Disabled.\n- Notion: Read Notion pages.\n- Salesforce: Enterprise.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(t, &includeDisabled, tt.includeDisabled)
+			setFlag(t, &includeEnterprise, tt.includeEnterprise)
+			setFlag(t, &includeDeprecated, false)
+			var out strings.Builder
+			if err := writeLLMsTxt(&out, h); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
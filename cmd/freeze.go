@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"log"
+	"maps"
+	"slices"
+
+	"github.com/blaxel-ai/mcp-hub/internal/git"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/spf13/cobra"
+)
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Pin the commit of every repository in a lockfile",
+	Long:  `freeze is a CLI tool to resolve the branch of every repository to a commit and write them to a lockfile used by import --locked`,
+	Run:   runFreeze,
+}
+
+func init() {
+	freezeCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	freezeCmd.Flags().StringVar(&lockPath, "lockfile", hub.LockFile, "The path of the lockfile to write")
+	freezeCmd.Flags().StringVar(&proxy, "proxy", "", "The proxy to use to reach the repositories, defaults to the HTTP_PROXY and HTTPS_PROXY environment variables")
+	freezeCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	rootCmd.AddCommand(freezeCmd)
+}
+
+func runFreeze(cmd *cobra.Command, args []string) {
+	if configPath == "" {
+		configPath = "hub"
+	}
+
+	h, err := readHub()
+	handleError("load config", err)
+
+	lock := hub.Lock{Repositories: make(map[string]hub.LockedRepository)}
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		repository := h.Repositories[name]
+		// Local repositories are built from the hub checkout itself
+		if repository.Path != "" {
+			continue
+		}
+		commit := repository.Commit
+		if commit == "" {
+			commit, err = git.ResolveBranch(repository.Repository, repository.Branch, proxy)
+			handleError("resolve branch of "+name, err)
+		}
+		lock.Repositories[name] = hub.LockedRepository{Repository: repository.Repository, Branch: repository.Branch, Commit: commit}
+		log.Printf("Locked %s to %s", name, commit)
+	}
+	handleError("write lockfile", lock.Write(lockPath))
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// lockedConfig is the config of a MCP cloned from a repository and a branch, followed by its extra fields
+const lockedConfig = `repository: %s
+branch: %s
+displayName: Brave Search
+license: MIT
+icon: https://brave.com/logo.svg
+description: Search the web using Brave's search engine.
+longDescription: Search the web using Brave's search engine.
+integration: brave-search
+%s`
+
+// advanceBranch adds a commit to the checked out branch of a fixture repository and returns it
+func advanceBranch(t *testing.T, dir string) string {
+	t.Helper()
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{"index.js": "console.log('v2')"})
+	if err := worktree.AddGlob("."); err != nil {
+		t.Fatal(err)
+	}
+	signature := &object.Signature{Name: "hub", Email: "hub@example.com", When: fixtureTime.Add(24 * time.Hour)}
+	hash, err := worktree.Commit("v2", &git.CommitOptions{Author: signature, Committer: signature})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+func TestFreeze(t *testing.T) {
+	url, commits := gitFixture(t,
+		fixtureBranch{name: "main", files: map[string]string{"smithery.yaml": smitheryFixture}},
+		fixtureBranch{name: "fix-search", files: map[string]string{"index.js": "fixed"}},
+	)
+	dir := testConfig(t)
+	writeFiles(t, dir, map[string]string{
+		"hub/exa.yaml":    fmt.Sprintf(lockedConfig, url, "main", ""),
+		"hub/tavily.yaml": fmt.Sprintf(lockedConfig, url, "fix-search", ""),
+		"hub/serper.yaml": fmt.Sprintf(lockedConfig, url, "main", "commit: pinned\n"),
+	})
+
+	result := runCLI(t, dir, nil, "freeze", "-c", "hub")
+	if result.code != 0 {
+		t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+	}
+	lock, err := hub.ReadLock(filepath.Join(dir, hub.LockFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The local brave repository is built from the hub checkout and left out
+	want := map[string]hub.LockedRepository{
+		"exa":    {Repository: url, Branch: "main", Commit: commits["main"]},
+		"serper": {Repository: url, Branch: "main", Commit: "pinned"},
+		"tavily": {Repository: url, Branch: "fix-search", Commit: commits["fix-search"]},
+	}
+	if !reflect.DeepEqual(lock.Repositories, want) {
+		t.Errorf("got  %+v\nwant %+v", lock.Repositories, want)
+	}
+
+	writeFiles(t, dir, map[string]string{"hub/exa.yaml": fmt.Sprintf(lockedConfig, url, "unknown", "")})
+	result = runCLI(t, dir, nil, "freeze", "-c", "hub", "--lockfile", "other.lock")
+	if result.code != 1 || !strings.Contains(result.stderr, "branch unknown not found in "+url) {
+		t.Errorf("exit code %d, stderr:\n%s\nwant the unknown branch", result.code, result.stderr)
+	}
+}
+
+func TestImportLocked(t *testing.T) {
+	chdir(t, t.TempDir())
+	setFlag(t, &tag, "v1")
+	setFlag(t, &skipBuild, true)
+	setFlag(t, &lockPath, hub.LockFile)
+	url, commits := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"smithery.yaml": smitheryFixture}})
+	lock := hub.Lock{Repositories: map[string]hub.LockedRepository{
+		"brave": {Repository: url, Branch: "main", Commit: commits["main"]},
+	}}
+	if err := lock.Write(hub.LockFile); err != nil {
+		t.Fatal(err)
+	}
+	// The branch moved on since the hub was frozen
+	head := advanceBranch(t, url)
+
+	newHub := func() *hub.Hub {
+		return &hub.Hub{Repositories: map[string]*hub.Repository{
+			"brave":  {Repository: url, Branch: "main", SmitheryPath: "smithery.yaml", Dockerfile: "Dockerfile"},
+			"exa":    {Repository: url, Branch: "main", SmitheryPath: "smithery.yaml", Dockerfile: "Dockerfile"},
+			"tavily": {Repository: "https://github.com/hub/tavily.git", Branch: "main"},
+			"local":  {Path: "servers/local"},
+		}}
+	}
+
+	h := newHub()
+	if err := applyLock(h, []string{"brave", "local"}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := prepareRepository("brave", h.Repositories["brave"], &importResult{Name: "brave"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.cleanup()
+	if p.source.Commit != commits["main"] {
+		t.Errorf("cloned %s, want the locked commit %s rather than the head %s", p.source.Commit, commits["main"], head)
+	}
+
+	h = newHub()
+	h.Repositories["tavily"].Repository = url
+	lock.Repositories["tavily"] = hub.LockedRepository{Repository: "https://github.com/hub/tavily.git", Branch: "main", Commit: commits["main"]}
+	if err := lock.Write(hub.LockFile); err != nil {
+		t.Fatal(err)
+	}
+	want := "repository exa is not in the lockfile, run freeze again\n" +
+		"repository tavily is locked for https://github.com/hub/tavily.git but configured for " + url + ", run freeze again"
+	if err := applyLock(h, []string{"exa", "tavily"}); err == nil || err.Error() != want {
+		t.Errorf("got\n%v\nwant\n%s", err, want)
+	}
+}
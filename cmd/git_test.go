@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// fixtureBranch is a branch of a fixture git repository and the files of its single commit
+type fixtureBranch struct {
+	name  string
+	files map[string]string
+}
+
+// fixtureTime is the date of the first commit of the fixture repositories, the next ones are an hour apart
+var fixtureTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// gitFixture creates a git repository with a commit per branch, the first branch is the default one and the others
+// start from it. It returns the path of the repository and the commit of every branch.
+func gitFixture(t *testing.T, branches ...fixtureBranch) (string, map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName(branches[0].name)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := make(map[string]string)
+	for i, branch := range branches {
+		if i > 0 {
+			err := worktree.Checkout(&git.CheckoutOptions{
+				Hash:   plumbing.NewHash(commits[branches[0].name]),
+				Branch: plumbing.NewBranchReferenceName(branch.name),
+				Create: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		writeFiles(t, dir, branch.files)
+		if err := worktree.AddGlob("."); err != nil {
+			t.Fatal(err)
+		}
+		signature := &object.Signature{Name: "hub", Email: "hub@example.com", When: fixtureTime.Add(time.Duration(i) * time.Hour)}
+		hash, err := worktree.Commit("commit "+branch.name, &git.CommitOptions{Author: signature, Committer: signature})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits[branch.name] = hash.String()
+	}
+	if len(branches) > 1 {
+		if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branches[0].name)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, commits
+}
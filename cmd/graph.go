@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/spf13/cobra"
+)
+
+var graphFormat string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Graph the relationships between repositories",
+	Long:  `graph is a CLI tool to connect the repositories sharing an integration, a source repository or tags`,
+	Run:   runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "The graph format, dot or mermaid")
+	graphCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	rootCmd.AddCommand(graphCmd)
+}
+
+// graphEdge connects two repositories, the labels are what they share
+type graphEdge struct {
+	from, to string
+	labels   []string
+}
+
+func runGraph(cmd *cobra.Command, args []string) {
+	if configPath == "" {
+		configPath = "hub"
+	}
+	if graphFormat != "dot" && graphFormat != "mermaid" {
+		log.Printf("Unknown format %s, expected dot or mermaid", graphFormat)
+		os.Exit(1)
+	}
+
+	h, err := readHub()
+	handleError("load config", err)
+	edges := graphEdges(h)
+	if graphFormat == "mermaid" {
+		writeMermaid(os.Stdout, h, edges)
+	} else {
+		writeDot(os.Stdout, h, edges)
+	}
+}
+
+// graphEdges returns an edge for every pair of repositories sharing an integration, a source repository or a tag,
+// sorted by repository names
+func graphEdges(h *hub.Hub) []graphEdge {
+	groups := make(map[string][]string)
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		repository := h.Repositories[name]
+		if repository.Integration != "" {
+			groups["integration: "+repository.Integration] = append(groups["integration: "+repository.Integration], name)
+		}
+		if repository.Repository != "" {
+			source := strings.TrimSuffix(repository.Repository, ".git")
+			groups["source: "+source] = append(groups["source: "+source], name)
+		}
+		for _, tag := range repository.Tags {
+			groups["tag: "+tag] = append(groups["tag: "+tag], name)
+		}
+	}
+
+	labels := make(map[[2]string][]string)
+	for _, label := range slices.Sorted(maps.Keys(groups)) {
+		names := groups[label]
+		for i, from := range names {
+			for _, to := range names[i+1:] {
+				labels[[2]string{from, to}] = append(labels[[2]string{from, to}], label)
+			}
+		}
+	}
+
+	var edges []graphEdge
+	for _, pair := range slices.SortedFunc(maps.Keys(labels), func(a, b [2]string) int {
+		return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+	}) {
+		edges = append(edges, graphEdge{from: pair[0], to: pair[1], labels: labels[pair]})
+	}
+	return edges
+}
+
+func writeDot(out io.Writer, h *hub.Hub, edges []graphEdge) {
+	fmt.Fprintln(out, "graph hub {")
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		fmt.Fprintf(out, "  %q;\n", name)
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(out, "  %q -- %q [label=%q];\n", edge.from, edge.to, strings.Join(edge.labels, "\n"))
+	}
+	fmt.Fprintln(out, "}")
+}
+
+// mermaidUnsafe matches the characters mermaid does not accept in node ids
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func writeMermaid(out io.Writer, h *hub.Hub, edges []graphEdge) {
+	// The index of the repository keeps the ids unique once sanitized, e.g. for foo-bar and foo.bar
+	ids := make(map[string]string)
+	fmt.Fprintln(out, "graph LR")
+	for i, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		ids[name] = fmt.Sprintf("%s_%d", mermaidUnsafe.ReplaceAllString(name, "_"), i)
+		fmt.Fprintf(out, "  %s[\"%s\"]\n", ids[name], name)
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(out, "  %s ---|\"%s\"| %s\n", ids[edge.from], strings.Join(edge.labels, "<br>"), ids[edge.to])
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+func TestGraphEdges(t *testing.T) {
+	h := &hub.Hub{Repositories: map[string]*hub.Repository{
+		"github":  {Integration: "github", Repository: "https://github.com/hub/servers.git"},
+		"gitlab":  {Integration: "gitlab", Repository: "https://github.com/hub/servers"},
+		"octokit": {Integration: "github", Tags: []string{"git"}},
+		"notion":  {Integration: "notion", Tags: []string{"git"}},
+	}}
+	want := []graphEdge{
+		{from: "github", to: "gitlab", labels: []string{"source: https://github.com/hub/servers"}},
+		{from: "github", to: "octokit", labels: []string{"integration: github"}},
+		{from: "notion", to: "octokit", labels: []string{"tag: git"}},
+	}
+	if got := graphEdges(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	h := &hub.Hub{Repositories: map[string]*hub.Repository{
+		"foo-bar": {Integration: "foo"},
+		"foo.bar": {Integration: "foo"},
+	}}
+	var out strings.Builder
+	writeMermaid(&out, h, graphEdges(h))
+	want := `graph LR
+  foo_bar_0["foo-bar"]
+  foo_bar_1["foo.bar"]
+  foo_bar_0 ---|"integration: foo"| foo_bar_1
+`
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+}
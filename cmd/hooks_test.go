@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImportHooks(t *testing.T) {
+	tests := []struct {
+		name   string
+		hooks  string
+		code   int
+		stderr string
+		want   []string
+	}{
+		{
+			name:  "pre-hook before the build",
+			hooks: "preHooks:\n  - echo generated > generated.txt\npostHooks:\n  - test -f generated.txt\n",
+			want:  []string{"build with generated.txt"},
+		},
+		{
+			name:   "failing pre-hook",
+			hooks:  "preHooks:\n  - exit 2\n",
+			code:   1,
+			stderr: `pre-build hook "exit 2" failed: exit status 2`,
+		},
+		{
+			name:   "failing post-hook",
+			hooks:  "postHooks:\n  - echo missing dist >&2; exit 1\n",
+			code:   1,
+			stderr: `post-build hook "echo missing dist >&2; exit 1" failed: exit status 1: missing dist`,
+			want:   []string{"build without generated.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "src")
+			writeFiles(t, dir, map[string]string{
+				"src/Dockerfile": "FROM node:22-alpine\n",
+				"hub/brave.yaml": fmt.Sprintf(braveConfig, src) + tt.hooks,
+			})
+			builds := filepath.Join(t.TempDir(), "builds.log")
+			// The build records whether the pre-hook already generated its file
+			fakeTool(t, "docker", `case "$1" in
+build|buildx) if [ -f `+src+`/generated.txt ]; then echo "build with generated.txt"; else echo "build without generated.txt"; fi >> `+builds+` ;;
+esac`)
+			result := runCLI(t, dir, nil, "import", "-c", "hub", "-d", "--tag", "v1")
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			if !strings.Contains(result.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", result.stderr, tt.stderr)
+			}
+			if got := toolCalls(t, builds); !slices.Equal(got, tt.want) {
+				t.Errorf("builds %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
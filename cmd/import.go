@@ -2,24 +2,31 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/blaxel-ai/mcp-hub/internal/catalog"
 	"github.com/blaxel-ai/mcp-hub/internal/docker"
+	huberrors "github.com/blaxel-ai/mcp-hub/internal/errors"
 	"github.com/blaxel-ai/mcp-hub/internal/git"
 	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/blaxel-ai/mcp-hub/internal/secrets"
 	"github.com/blaxel-ai/mcp-hub/internal/smithery"
 	"github.com/spf13/cobra"
 )
 
 const (
-	tmpDir       = "tmp"
-	githubPrefix = "https://github.com/"
-	dockerfile   = "Dockerfile"
+	tmpDir = "tmp"
 )
 
 var importCmd = &cobra.Command{
@@ -30,13 +37,56 @@ var importCmd = &cobra.Command{
 }
 
 func init() {
-	importCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files")
+	importCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
 	importCmd.Flags().BoolVarP(&push, "push", "p", false, "Push the images to the registry")
 	importCmd.Flags().StringVarP(&registry, "registry", "r", "ghcr.io/blaxel-ai/hub", "The registry to push the images to")
+	importCmd.Flags().BoolVar(&forcePush, "force-push", false, "Push the images even when the registry already has them")
+	importCmd.Flags().BoolVar(&registryInsecure, "registry-insecure", false, "Allow pushing to a registry over plain HTTP, it must be an insecure registry of the docker daemon, the registry checks and attachments use plain HTTP too")
 	importCmd.Flags().StringVarP(&mcp, "mcp", "m", "", "The MCP to import, if not provided, all MCPs will be imported")
+	importCmd.Flags().StringVar(&group, "group", "", "Import the repositories of this group of "+hub.GroupsFile+", in addition to --mcp")
 	importCmd.Flags().BoolVarP(&skipBuild, "skip-build", "s", false, "Skip building the image")
-	importCmd.Flags().StringVarP(&tag, "tag", "t", "latest", "The tag to use for the image")
+	importCmd.Flags().BoolVar(&catalogOnly, "catalog-only", false, "Only clone the repositories and generate their catalog, to the file store unless --catalog-store is set, without building nor pushing")
+	importCmd.Flags().StringVar(&maxImageSize, "max-image-size", "", "Fail the build of an image larger than this size, e.g. 500m or 2g, overridden by the maxImageSize of a repository")
+	importCmd.Flags().BoolVar(&pull, "pull", false, "Pull the base images before building to fail fast on bad references")
+	importCmd.Flags().BoolVar(&lintDockerfile, "lint-dockerfile", false, "Check the COPY and ADD sources and stages of the injected Dockerfile exist before building")
+	importCmd.Flags().StringVarP(&tag, "tag", "t", "", "The tag to use for the image, defaults to the VERSION file of the config, git describe or latest")
+	importCmd.Flags().StringSliceVar(&platforms, "platform", nil, "The platforms to build the image for, defaults to the platform of the docker daemon")
+	importCmd.Flags().StringVar(&profileName, "profile", "", "The profile of profiles.yaml setting the registry, tag, platforms and push, explicit flags take precedence")
+	importCmd.Flags().StringVar(&profilesPath, "profiles", "profiles.yaml", "The path to the profiles file")
+	importCmd.Flags().BoolVar(&latest, "latest", false, "Also tag and push the image as latest")
+	importCmd.Flags().BoolVar(&sign, "sign", false, "Sign the pushed images with cosign, keyless unless COSIGN_KEY is set")
+	importCmd.Flags().BoolVar(&sbom, "sbom", false, "Generate a SBOM of the pushed images with syft and attach it with cosign")
+	importCmd.Flags().BoolVar(&signRequired, "sign-required", false, "Fail instead of warning when cosign or syft are not installed")
 	importCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug mode, will not save the catalog")
+	importCmd.Flags().StringVar(&proxy, "proxy", "", "The proxy to use for clone and build, defaults to the HTTP_PROXY and HTTPS_PROXY environment variables")
+	importCmd.Flags().IntVar(&maxLongDescription, "max-long-description", 2000, "The maximum length of a long description, 0 to disable the check")
+	importCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	importCmd.Flags().StringVar(&vocabularyPath, "vocabulary", "", "The file listing the allowed tags and categories, not checked when empty")
+	importCmd.Flags().BoolVar(&inlineIcons, "inline-icons", false, "Download the remote icons and store them in the catalog as data URIs")
+	importCmd.Flags().BoolVar(&sanitize, "sanitize", false, "Strip scripts and disallowed HTML elements from the descriptions of the catalog")
+	importCmd.Flags().StringVar(&visibility, "visibility", hub.VisibilityPublic, "The visibility of the catalog, public leaves out the internal repositories and fields")
+	importCmd.Flags().BoolVar(&includeDisabled, "include-disabled", false, "Include the disabled repositories in the catalog")
+	importCmd.Flags().StringVar(&reportOut, "report-out", "", "Write a JSON report of the import of every repository to this path")
+	importCmd.Flags().IntVar(&cloneConcurrency, "clone-concurrency", 4, "The maximum number of repositories cloned at once")
+	importCmd.Flags().IntVar(&buildConcurrency, "build-concurrency", 1, "The maximum number of images built at once")
+	importCmd.Flags().StringVar(&since, "since", "", "Only import the repositories whose config or local path changed since this git ref, all of them when _defaults.yaml changed")
+	importCmd.Flags().BoolVar(&resume, "resume", false, "Skip the repositories imported successfully by the previous run of the same tag and registry")
+	importCmd.Flags().StringVar(&statePath, "state-file", importStateFile, "The file recording the repositories imported successfully, removed once all of them are")
+	importCmd.Flags().StringVar(&mirrorRemote, "mirror-remote", "", "The base URL of a git mirror to clone from when it has the repository and to push the clones to, repositories are mirrored to <mirror>/<host>/<path>")
+	importCmd.Flags().BoolVar(&archive, "archive", false, "Download the source archive of the GitHub repositories instead of cloning them, the builds are then not reproducible")
+	importCmd.Flags().StringSliceVar(&allowLicenses, "allow-licenses", nil, "Fail when the license expression of a repository can't be satisfied with these SPDX identifiers, one license of an OR is enough")
+	importCmd.Flags().BoolVar(&locked, "locked", false, "Clone the commits pinned in the lockfile instead of the branch heads")
+	importCmd.Flags().StringVar(&lockPath, "lockfile", hub.LockFile, "The path of the lockfile used by --locked")
+	importCmd.Flags().BoolVar(&checkEnv, "check-env", false, "Check that the required environment variables of every repository are set before building")
+	importCmd.Flags().StringVar(&catalogStore, "catalog-store", catalog.StoreAPI, "Where to save the catalog: api for the control plane, file or gcs")
+	importCmd.Flags().StringVar(&catalogStorePath, "catalog-store-path", "", "The directory of the file store, defaults to catalog, or the bucket[/prefix] of the gcs store")
+	importCmd.Flags().BoolVar(&skipSchemaValidation, "skip-schema-validation", false, "Save the catalogs without validating them against the catalog JSON schema")
+	importCmd.Flags().BoolVar(&catalogToRegistry, "catalog-to-registry", false, "Push the catalog to the registry as an OCI artifact referencing the image with oras instead of saving it")
+	importCmd.Flags().BoolVar(&referrers, "referrers", false, "Also attach the catalog, and the SBOM with --sbom, to the pushed image as OCI referrers with oras")
+	importCmd.Flags().BoolVar(&scanSecrets, "scan-secrets", false, "Fail when the value of a secret of the MCP is found in the env or the layer commands of the built image")
+	importCmd.Flags().StringVar(&secretProvider, "secret-provider", secrets.ProviderEnv, "The provider to read the secrets scanned for from (env, vault)")
+	importCmd.Flags().BoolVar(&buildCache, "build-cache", false, "Import and export the build cache from the registry")
+	importCmd.Flags().BoolVar(&reproducible, "reproducible", false, "Set the timestamps of the images to the date of their commit so rebuilds of a commit are identical, the images then carry no build time for prune")
 	rootCmd.AddCommand(importCmd)
 }
 
@@ -45,120 +95,816 @@ func runImport(cmd *cobra.Command, args []string) {
 		configPath = "hub"
 	}
 
-	hub := hub.Hub{}
-	handleError("read config file", hub.Read(configPath))
-	handleError("validate config file", hub.ValidateWithDefaultValues())
+	explicitTag, err := applyProfile(cmd)
+	handleError("apply profile", err)
+	if catalogOnly {
+		// Previews never touch the registry, and default to catalog files rather than the control plane
+		skipBuild, push = true, false
+		if !cmd.Flags().Changed("catalog-store") {
+			catalogStore = catalog.StoreFile
+		}
+	}
+
+	if maxImageSize != "" {
+		_, err := hub.ParseSize(maxImageSize)
+		handleError("parse max image size", err)
+	}
+	hub, err := readHub()
+	handleError("load config", err)
+	tag, err = resolveTag(explicitTag, hub.Path)
+	handleError("resolve tag", err)
+
+	if push && registryInsecure {
+		handleError("check insecure registry", docker.CheckInsecureRegistry(context.Background(), registry))
+	}
+	if !debug && !catalogToRegistry {
+		// Fail before building on a misconfigured store
+		_, err := catalog.NewCatalogStore(catalogStore, catalogStorePath)
+		handleError("create catalog store", err)
+	}
+	if catalogToRegistry && (!push || skipBuild) {
+		log.Printf("--catalog-to-registry requires --push and a build, the catalog references the pushed image")
+		os.Exit(1)
+	}
+	if referrers && (!push || skipBuild) {
+		log.Printf("--referrers requires --push and a build, the referrers are attached to the pushed image")
+		os.Exit(1)
+	}
 
 	setupTempDirectory()
 	defer os.RemoveAll(tmpDir)
 
+	changed, err := changedRepositories(hub)
+	handleError("list changed repositories", err)
+	names, err := selectRepositories(hub, changed)
+	handleError("select repositories", err)
+	state, err := newImportState(statePath, resume)
+	handleError("read import state", err)
+	names = state.pending(names)
+	if len(allowLicenses) > 0 {
+		handleError("check licenses", checkLicenses(hub, names))
+	}
+	if locked {
+		handleError("apply lockfile", applyLock(hub, names))
+	}
+	if checkEnv {
+		handleError("check environment", checkRequiredEnv(hub, names))
+	}
+
+	results := importRepositories(hub, names, state, importSteps{prepare: prepareRepository, build: buildRepository})
+	saveReport(results)
+	for _, result := range results {
+		if result.Error != "" {
+			log.Printf("Run again with --resume to skip the repositories imported successfully")
+			os.Exit(1)
+		}
+	}
+	state.clear()
+}
+
+// importSteps are the clone and build steps of the import of a repository
+type importSteps struct {
+	prepare func(name string, repository *hub.Repository, result *importResult) (*preparedRepository, error)
+	build   func(p *preparedRepository, result *importResult) (*catalog.Catalog, error)
+}
+
+// importRepositories clones up to cloneConcurrency repositories at once, feeding up to buildConcurrency builds,
+// no repository is started once one of them failed, they are reported as aborted or skipped.
+// The repositories imported successfully are recorded in the state.
+func importRepositories(hub *hub.Hub, names []string, state *importState, steps importSteps) []importResult {
+	results := make([]importResult, len(names))
+	for i, name := range names {
+		results[i] = importResult{Name: name, Status: statusSkipped}
+	}
+	starts := make([]time.Time, len(names))
+	var failed atomic.Bool
+	fail := func(i int, err error) {
+		log.Printf("Failed to process repository %s: %v", names[i], err)
+		failed.Store(true)
+	}
+
+	cloned := make(chan *preparedRepository)
+	indexes := make(map[*preparedRepository]int)
+	var mu sync.Mutex
+	go func() {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, max(cloneConcurrency, 1))
+		for i, name := range names {
+			sem <- struct{}{}
+			if failed.Load() {
+				<-sem
+				break
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				starts[i] = time.Now()
+				p, err := steps.prepare(name, hub.Repositories[name], &results[i])
+				if err != nil {
+					results[i].finish(starts[i], err)
+					fail(i, err)
+					return
+				}
+				mu.Lock()
+				indexes[p] = i
+				mu.Unlock()
+				cloned <- p
+			}()
+		}
+		wg.Wait()
+		close(cloned)
+	}()
+
+	var wg sync.WaitGroup
+	for range max(buildConcurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range cloned {
+				mu.Lock()
+				i := indexes[p]
+				mu.Unlock()
+				if failed.Load() {
+					// Drop the clones waiting for a build, the import is aborted
+					p.cleanup()
+					results[i].Status = statusAborted
+					continue
+				}
+				_, err := steps.build(p, &results[i])
+				p.cleanup()
+				results[i].finish(starts[i], err)
+				if err != nil {
+					fail(i, err)
+				} else {
+					state.complete(names[i])
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// changedRepositories returns the repositories whose config or local source changed since the --since ref,
+// nil for all of them
+func changedRepositories(h *hub.Hub) ([]string, error) {
+	if since == "" {
+		return nil, nil
+	}
+	names, err := h.ChangedSince(since)
+	if err != nil {
+		return nil, err
+	}
+	if names == nil {
+		log.Printf("%s changed since %s, importing all repositories", hub.DefaultsFile, since)
+		return nil, nil
+	}
+	log.Printf("%d repositories changed since %s", len(names), since)
+	return names, nil
+}
+
+// checkLicenses returns an error listing the repositories whose license is not in --allow-licenses
+func checkLicenses(h *hub.Hub, names []string) error {
+	var errs []error
+	for _, name := range names {
+		repository := h.Repositories[name]
+		if !repository.LicenseAllowed(allowLicenses) {
+			errs = append(errs, fmt.Errorf("repository %s license %s is not allowed", name, repository.License))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyLock pins the selected repositories to the commits of the lockfile
+func applyLock(h *hub.Hub, names []string) error {
+	lock, err := hub.ReadLock(lockPath)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range names {
+		if err := h.Repositories[name].ApplyLock(name, lock); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// selectRepositories returns the sorted names of the repositories to import, limited to changed unless it's nil.
+// --mcp and --group select the union of the MCP and the members of the group.
+func selectRepositories(hub *hub.Hub, changed []string) ([]string, error) {
+	var members []string
+	if group != "" {
+		var err error
+		if members, err = hub.Group(group); err != nil {
+			return nil, err
+		}
+	}
+	var names []string
 	for name, repository := range hub.Repositories {
-		if mcp != "" && mcp != name {
+		if (mcp != "" || group != "") && mcp != name && !slices.Contains(members, name) {
+			continue
+		}
+		if changed != nil && !slices.Contains(changed, name) {
+			continue
+		}
+		if !repository.InCatalog(includeDisabled) {
+			log.Printf("Skipping disabled repository %s", name)
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// checkRequiredEnv returns an error listing every required environment variable missing from the environment
+// across the repositories, they are loaded without being built nor saved to resolve their entrypoint
+func checkRequiredEnv(hub *hub.Hub, names []string) error {
+	prevSkipBuild, prevDebug := skipBuild, debug
+	skipBuild, debug = true, true
+	defer func() {
+		skipBuild, debug = prevSkipBuild, prevDebug
+	}()
+
+	var errs []error
+	for _, name := range names {
+		repository := hub.Repositories[name]
+		if repository.Disabled {
 			continue
 		}
-		_, err := processRepository(name, repository)
+		c, err := processRepository(name, repository, &importResult{Name: name})
 		if err != nil {
-			log.Printf("Failed to process repository %s: %v", name, err)
-			os.Exit(1)
+			return fmt.Errorf("failed to load repository %s: %w", name, err)
+		}
+		// Internal repositories have no artifact in a public catalog
+		if len(c.Artifacts) == 0 {
+			continue
+		}
+		artifact := c.Artifacts[0]
+		for _, key := range slices.Sorted(maps.Keys(artifact.Entrypoint.Env)) {
+			if err := checkEnvironmentVariable(name, artifact, key, artifact.Entrypoint.Env[key], os.Getenv(key)); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	}
+	return errors.Join(errs...)
 }
 
-func processRepository(name string, repository *hub.Repository) (*catalog.Catalog, error) {
-	var repoPath string
-	imageName := fmt.Sprintf("%s:%s", strings.ToLower(name), tag)
-	if repository.Path != "" {
-		repoPath = repository.Path
-	} else {
-		repoPath = fmt.Sprintf("%s/%s/%s", tmpDir, strings.TrimPrefix(repository.Repository, githubPrefix), repository.Branch)
-		defer git.DeleteRepository(repoPath)
+// saveReport writes the import report when --report-out is set
+func saveReport(results []importResult) {
+	if reportOut == "" {
+		return
+	}
+	if err := writeReport(reportOut, results); err != nil {
+		log.Printf("Failed to write report %s: %v", reportOut, err)
 	}
+}
 
-	if repository.Disabled {
-		c := catalog.Catalog{}
-		handleError("load catalog", c.Load(name, repository, imageName, &smithery.SmitheryConfig{}))
-		if !debug {
-			handleError("save catalog", c.Save())
+// preparedRepository is a repository cloned and parsed, ready to be built
+type preparedRepository struct {
+	name       string
+	repository *hub.Repository
+	repoPath   string
+	tags       []string
+	imageName  string
+	cfg        *smithery.SmitheryConfig
+	source     *catalog.Source
+	// archived is true when the source is a downloaded archive, it has no git history
+	archived bool
+	cleanup  func()
+}
+
+func processRepository(name string, repository *hub.Repository, result *importResult) (*catalog.Catalog, error) {
+	p, err := prepareRepository(name, repository, result)
+	if err != nil {
+		return nil, err
+	}
+	defer p.cleanup()
+	return buildRepository(p, result)
+}
+
+// prepareRepository clones the repository and parses its smithery config, the caller must call cleanup
+// once done with the clone
+func prepareRepository(name string, repository *hub.Repository, result *importResult) (*preparedRepository, error) {
+	p := &preparedRepository{name: name, repository: repository, cleanup: func() {}}
+	tags, err := imageTags(tag, latest)
+	if err != nil {
+		return nil, err
+	}
+	if push && repository.ComingSoon {
+		if tags, err = comingSoonTags(name, tags); err != nil {
+			return nil, err
 		}
-		return &c, nil
+	}
+	p.tags = tags
+	p.imageName = fmt.Sprintf("%s:%s", strings.ToLower(name), tags[0])
+	if repository.Disabled {
+		p.cfg = &smithery.SmitheryConfig{}
+		return p, nil
 	}
 
-	if repository.Path == "" {
-		if _, err := git.CloneRepository(repoPath, repository.Branch, repository.Repository); err != nil {
-			return nil, fmt.Errorf("clone repository: %w", err)
+	// Fail before cloning if the Dockerfile template is missing
+	if !skipBuild && repository.Dockerfile == docker.HubDockerfile {
+		if err := docker.CheckTemplate(name); err != nil {
+			return nil, err
 		}
 	}
 
-	var cfg *smithery.SmitheryConfig
+	if repository.Path != "" {
+		p.repoPath = repository.Path
+		p.source = &catalog.Source{Path: repository.Path, Note: "built from a local path, no commit is available"}
+	} else {
+		// Clones are per repository as several of them can share a git repository and be processed at once
+		p.repoPath = git.CachePath(filepath.Join(tmpDir, strings.ToLower(name)), repository.Repository, repository.Branch)
+		p.cleanup = func() {
+			if err := git.DeleteRepository(tmpDir, p.repoPath); err != nil {
+				log.Printf("Failed to delete repository %s: %v", p.repoPath, err)
+			}
+		}
+		source, archived, err := fetchRepository(p.repoPath, repository)
+		if err != nil {
+			p.cleanup()
+			return nil, &huberrors.CloneError{Repository: name, Err: err}
+		}
+		p.source, p.archived = source, archived
+		result.Cloned = true
+	}
 
 	if repository.Smithery != nil {
-		cfg = repository.Smithery
-		parsedCommand, err := smithery.ExecuteCommandFunction(cfg.StartCommand.CommandFunction, cfg.StartCommand.ConfigSchema.Properties)
+		p.cfg = repository.Smithery
+		parsedCommand, err := smithery.ExecuteCommandFunction(p.cfg.StartCommand.CommandFunction, p.cfg.StartCommand.ConfigSchema.Properties)
 		if err != nil {
+			p.cleanup()
 			return nil, fmt.Errorf("execute command function: %w", err)
 		}
-		parsedCommand.Type = cfg.StartCommand.Type
-		cfg.ParsedCommand = parsedCommand
+		parsedCommand.Type = p.cfg.StartCommand.Type
+		p.cfg.ParsedCommand = parsedCommand
 	} else {
-		tmpCfg, err := smithery.Parse(filepath.Join(repoPath, repository.SmitheryPath))
+		tmpCfg, err := smithery.Parse(filepath.Join(p.repoPath, repository.SmitheryPath))
+		if errors.Is(err, smithery.ErrNotFound) {
+			p.cleanup()
+			return nil, fmt.Errorf("no smithery file at %q in repository %s, set smitheryPath to its location or provide an inline smithery config: %w", repository.SmitheryPath, name, err)
+		}
 		if err != nil {
+			p.cleanup()
 			return nil, fmt.Errorf("parse smithery file: %w", err)
 		}
-		cfg = &tmpCfg
+		p.cfg = &tmpCfg
+	}
+
+	declared := slices.Concat(repository.Secrets, repository.HiddenSecrets)
+	warnings, err := smithery.UndeclaredReferences(p.cfg, declared)
+	if err != nil {
+		p.cleanup()
+		return nil, fmt.Errorf("check smithery references: %w", err)
 	}
+	warnings = append(warnings, smithery.UndeclaredRequired(p.cfg, declared)...)
+	for _, warning := range warnings {
+		log.Printf("Warning: repository %s: %s", name, warning)
+	}
+	return p, nil
+}
 
-	buildTo := fmt.Sprintf("%s/%s", strings.ToLower(registry), imageName)
+// fetchRepository downloads the source archive of the repository with --archive when its host serves one,
+// and clones it otherwise, the returned bool is true for an archive
+func fetchRepository(repoPath string, repository *hub.Repository) (*catalog.Source, bool, error) {
+	clonedAt := time.Now().UTC()
+	source := &catalog.Source{Repository: repository.Repository, Branch: repository.Branch, ClonedAt: &clonedAt}
+	if archive {
+		if _, ok := git.ArchiveURL(repository.Repository, repository.Branch); ok {
+			err := git.DownloadArchive(repoPath, repository.Branch, repository.Commit, repository.Repository, proxy)
+			if err == nil {
+				// Archives have no history, only a pinned commit is known
+				source.Commit = repository.Commit
+				return source, true, nil
+			}
+			log.Printf("Warning: falling back to clone for %s: %v", repository.Repository, err)
+			if err := git.DeleteRepository(tmpDir, repoPath); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	if err := cloneRepository(repoPath, repository); err != nil {
+		return nil, false, err
+	}
+	commit, err := git.HeadCommit(repoPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("resolve commit: %w", err)
+	}
+	source.Commit = commit
+	return source, false, nil
+}
+
+// cloneRepository clones from the mirror of --mirror-remote when it has the branch, and otherwise from the repository,
+// pushing the clone to the mirror for the next runs
+func cloneRepository(repoPath string, repository *hub.Repository) error {
+	if mirrorRemote == "" {
+		_, err := git.CloneRepository(repoPath, repository.Branch, repository.Commit, repository.Repository, proxy)
+		return err
+	}
+	mirrorURL, err := git.MirrorURL(mirrorRemote, repository.Repository)
+	if err != nil {
+		return err
+	}
+	if _, err = git.CloneRepository(repoPath, repository.Branch, repository.Commit, mirrorURL, proxy); err == nil {
+		return nil
+	}
+	log.Printf("Warning: cloning %s from the repository, the mirror %s is not usable: %v", repository.Repository, mirrorURL, err)
+	if err := git.DeleteRepository(tmpDir, repoPath); err != nil {
+		return err
+	}
+	if _, err := git.CloneRepository(repoPath, repository.Branch, repository.Commit, repository.Repository, proxy); err != nil {
+		return err
+	}
+	if err := git.PushMirror(repoPath, repository.Branch, mirrorURL, proxy); err != nil {
+		log.Printf("Warning: failed to mirror %s: %v", repository.Repository, err)
+	}
+	return nil
+}
+
+// buildRepository builds and pushes the image of a prepared repository and saves its catalog
+func buildRepository(p *preparedRepository, result *importResult) (*catalog.Catalog, error) {
+	name, repository := p.name, p.repository
+	if repository.Disabled {
+		c := catalog.Catalog{}
+		if err := c.Load(name, repository, p.imageName, p.cfg); err != nil {
+			return nil, fmt.Errorf("load catalog: %w", err)
+		}
+		if err := c.Transform(repository); err != nil {
+			return nil, fmt.Errorf("transform catalog: %w", err)
+		}
+		if err := c.ResolveIcons(inlineIcons); err != nil {
+			return nil, fmt.Errorf("resolve icons: %w", err)
+		}
+		if visibility != "" {
+			if err := c.FilterVisibility(visibility, repository); err != nil {
+				return nil, fmt.Errorf("filter catalog: %w", err)
+			}
+		}
+		if sanitize {
+			c.Sanitize()
+		}
+		if !debug {
+			if err := saveCatalog(&c); err != nil {
+				return nil, fmt.Errorf("save catalog: %w", err)
+			}
+		}
+		return &c, nil
+	}
+
+	buildTo := fmt.Sprintf("%s/%s", strings.ToLower(registry), p.imageName)
+	var imageNames []string
+	for _, t := range p.tags {
+		imageNames = append(imageNames, fmt.Sprintf("%s/%s:%s", strings.ToLower(registry), strings.ToLower(name), t))
+	}
 	if !skipBuild {
 		deps := manageDeps(repository)
-		if err := buildAndPushImage(cfg, name, repository.SmitheryPath, repoPath, strings.TrimSuffix(repository.Dockerfile, "/Dockerfile"), buildTo, deps); err != nil {
+		labels := imageLabels(repository)
+		artifact, err := imageArtifact(name, repository, buildTo, p.cfg)
+		if err != nil {
+			return nil, err
+		}
+		labels[docker.CatalogLabel] = artifact
+		opts := docker.BuildOptions{
+			Ignore:     repository.Ignore,
+			BuildArgs:  docker.ProxyBuildArgs(proxy),
+			Labels:     labels,
+			Pull:       pull,
+			Lint:       lintDockerfile,
+			Target:     repository.BuildTarget,
+			Network:    repository.BuildNetwork,
+			Platforms:  platforms,
+			ExtraFiles: repository.ExtraFiles,
+			PreHooks:   repository.PreHooks,
+			MaxSize:    imageSizeLimit(repository),
+			PostHooks:  repository.PostHooks,
+		}
+		if repository.BuildContext != "" {
+			opts.Context = filepath.Join(p.repoPath, repository.BuildContext)
+		}
+		if buildCache {
+			opts.CacheRef = docker.CacheRef(buildTo)
+		}
+		if reproducible && p.archived {
+			log.Printf("Warning: repository %s was downloaded as an archive without history, the build is not reproducible", name)
+		} else if reproducible {
+			if epoch, err := git.CommitEpoch(p.repoPath); err == nil {
+				opts.SourceDateEpoch = epoch
+			} else {
+				log.Printf("Warning: no commit date for repository %s, the build is not reproducible: %v", name, err)
+			}
+		}
+		dockerfileDir, dockerfileName := docker.SplitDockerfile(repository.Dockerfile)
+		if err := buildAndPushImage(p.cfg, name, repository, p.repoPath, dockerfileDir, dockerfileName, imageNames, deps, opts, result); err != nil {
 			return nil, fmt.Errorf("build and push image: %w", err)
 		}
 	}
 
 	c := catalog.Catalog{}
-	handleError("load catalog", c.Load(name, repository, buildTo, cfg))
+	if err := c.Load(name, repository, buildTo, p.cfg); err != nil {
+		return nil, fmt.Errorf("load catalog: %w", err)
+	}
+	for i := range c.Artifacts {
+		c.Artifacts[i].Source = p.source
+	}
+	if err := c.Transform(repository); err != nil {
+		return nil, fmt.Errorf("transform catalog: %w", err)
+	}
+	if err := c.ResolveIcons(inlineIcons); err != nil {
+		return nil, fmt.Errorf("resolve icons: %w", err)
+	}
+	if visibility != "" {
+		if err := c.FilterVisibility(visibility, repository); err != nil {
+			return nil, fmt.Errorf("filter catalog: %w", err)
+		}
+	}
+	if sanitize {
+		c.Sanitize()
+	}
 	if !debug {
-		handleError("save catalog", c.Save())
+		if !catalogToRegistry {
+			if err := saveCatalog(&c); err != nil {
+				return nil, fmt.Errorf("save catalog: %w", err)
+			}
+		}
+		if catalogToRegistry || referrers {
+			if err := attachCatalog(&c, imageNames[0], result); err != nil {
+				return nil, fmt.Errorf("attach catalog: %w", err)
+			}
+		}
 	}
 	return &c, nil
 }
 
-func buildAndPushImage(cfg *smithery.SmitheryConfig, name string, smitheryPath string, repoPath string, dockerfileDir string, imageName string, deps []string) error {
+// saveCatalog saves the catalog to the store selected by --catalog-store
+func saveCatalog(c *catalog.Catalog) error {
+	store, err := catalog.NewCatalogStore(catalogStore, catalogStorePath)
+	if err != nil {
+		return err
+	}
+	c.SkipValidation = skipSchemaValidation
+	return c.Save(store)
+}
+
+// attachCatalog pushes the catalog of a pushed image to the registry as an artifact referencing its digest
+func attachCatalog(c *catalog.Catalog, imageName string, result *importResult) error {
+	imageRef, err := pushedDigest(imageName, result)
+	if err != nil {
+		return err
+	}
+	for _, artifact := range c.Artifacts {
+		data, err := json.MarshalIndent(artifact, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := docker.AttachCatalog(context.Background(), imageRef, data, registryInsecure); err != nil {
+			return err
+		}
+		log.Printf("Attached catalog of %s to %s", artifact.Name, imageRef)
+	}
+	return nil
+}
+
+func buildAndPushImage(cfg *smithery.SmitheryConfig, name string, repository *hub.Repository, repoPath string, dockerfileDir string, dockerfileName string, imageNames []string, deps []string, opts docker.BuildOptions, result *importResult) error {
+	if err := docker.RunHooks(context.Background(), "pre-build", repoPath, opts.PreHooks); err != nil {
+		return &huberrors.BuildError{Repository: name, Err: err}
+	}
 	dockerfilePath, err := docker.Inject(
 		context.Background(),
 		name,
 		repoPath,
 		dockerfileDir,
-		dockerfile,
+		dockerfileName,
 		cfg.ParsedCommand.Entrypoint(),
 		deps,
+		opts.Target,
 	)
 	if err != nil {
-		return fmt.Errorf("inject command: %w", err)
+		return &huberrors.BuildError{Repository: name, Err: fmt.Errorf("inject command: %w", err)}
 	}
 
-	tmpDockerfilePath, err := docker.BuildImage(context.Background(), imageName, smitheryPath, dockerfileDir, dockerfilePath)
+	tmpDockerfilePath, err := docker.BuildImage(context.Background(), imageNames, repository.SmitheryPath, dockerfileDir, dockerfilePath, opts)
 	if err != nil {
-		return fmt.Errorf("build image: %w", err)
+		return &huberrors.BuildError{Repository: name, Err: err}
 	}
 
 	if err := os.Remove(tmpDockerfilePath); err != nil {
 		return fmt.Errorf("remove tmp dockerfile: %w", err)
 	}
+	if err := docker.RunHooks(context.Background(), "post-build", repoPath, opts.PostHooks); err != nil {
+		return &huberrors.BuildError{Repository: name, Err: err}
+	}
+	result.Built = true
+
+	if scanSecrets {
+		if err := scanImageSecrets(name, repository, cfg, imageNames[0]); err != nil {
+			return err
+		}
+	}
 
 	if push {
-		if err := docker.PushImage(context.Background(), imageName); err != nil {
-			return fmt.Errorf("push image: %w", err)
+		for _, imageName := range imageNames {
+			if !forcePush {
+				if ref := imagePushed(imageName); ref != "" {
+					log.Printf("Skipping push of %s, the registry already has this image", imageName)
+					result.SkippedPushes = append(result.SkippedPushes, imageName)
+					if result.remoteDigests == nil {
+						result.remoteDigests = make(map[string]string)
+					}
+					result.remoteDigests[imageName] = ref
+					continue
+				}
+			}
+			if err := docker.PushImage(context.Background(), imageName); err != nil {
+				return &huberrors.PushError{Repository: name, Image: imageName, Err: err}
+			}
+			result.Pushed = true
+		}
+		if sign || sbom {
+			if err := signImage(imageNames[0], result); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// imageSizeLimit returns the maximum size of the image of the repository in bytes, its maxImageSize taking precedence
+// over --max-image-size, 0 when none is set
+func imageSizeLimit(repository *hub.Repository) int64 {
+	limit := maxImageSize
+	if repository.MaxImageSize != "" {
+		limit = repository.MaxImageSize
+	}
+	if limit == "" {
+		return 0
+	}
+	// Both values are validated before the import starts
+	size, _ := hub.ParseSize(limit)
+	return size
+}
+
+// imagePushed returns the digest reference of the image in the registry when its tag already points to the local
+// image, a failed check is only a warning as the image is then pushed
+func imagePushed(imageName string) string {
+	ref, err := docker.ImagePushed(context.Background(), imageName, registryInsecure)
+	if err != nil {
+		log.Printf("Warning: could not compare %s to the registry, pushing it: %v", imageName, err)
+	}
+	return ref
+}
+
+// pushedDigest returns the digest reference of an image in the registry, the one read from the registry when its
+// push was skipped
+func pushedDigest(imageName string, result *importResult) (string, error) {
+	if ref, ok := result.remoteDigests[imageName]; ok {
+		return ref, nil
+	}
+	return docker.ImageDigest(context.Background(), imageName)
+}
+
+// scanImageSecrets fails when the value of a secret of the MCP environment is baked into the image, the other
+// variables of the environment are plain config and may legitimately be in it
+func scanImageSecrets(name string, repository *hub.Repository, cfg *smithery.SmitheryConfig, imageName string) error {
+	provider, err := secrets.NewSecretProvider(secretProvider)
+	if err != nil {
+		return err
+	}
+	c := catalog.Catalog{}
+	if err := c.Load(name, repository, imageName, cfg); err != nil {
+		return fmt.Errorf("load catalog: %w", err)
+	}
+	values := make(map[string]string)
+	var keys []string
+	for _, artifact := range c.Artifacts {
+		keys = append(keys, secretEnvKeys(artifact)...)
+	}
+	for _, key := range keys {
+		value, err := provider.Get(key)
+		if err != nil {
+			return fmt.Errorf("read secret %s: %w", key, err)
+		}
+		if value != "" {
+			values[key] = value
+		}
+	}
+	leaked, err := docker.ScanSecrets(context.Background(), imageName, values)
+	if err != nil {
+		return err
+	}
+	if len(leaked) > 0 {
+		return fmt.Errorf("image %s contains the value of %s", imageName, strings.Join(leaked, ", "))
+	}
+	return nil
+}
+
+// signImage signs and attaches the SBOM of a pushed image, missing tools are skipped unless signing is required
+func signImage(imageName string, result *importResult) error {
+	imageRef, err := pushedDigest(imageName, result)
+	if err != nil {
+		return err
+	}
+	skip := func(err error) error {
+		if errors.Is(err, docker.ErrToolNotFound) && !signRequired {
+			log.Printf("Warning: skipping signing of %s: %v", imageRef, err)
+			return nil
+		}
+		return err
+	}
+
+	if sign {
+		signature, err := docker.SignImage(context.Background(), imageRef)
+		if err != nil {
+			if err := skip(err); err != nil {
+				return err
+			}
+		} else {
+			log.Printf("Signed image %s, signature %s", imageRef, signature)
+		}
+	}
+	if sbom {
+		if err := docker.AttachSBOM(context.Background(), imageRef, referrers, registryInsecure); err != nil {
+			if err := skip(err); err != nil {
+				return err
+			}
+		} else {
+			log.Printf("Attached SBOM to image %s", imageRef)
+		}
+	}
+	return nil
+}
+
+// imageTags returns the tags to build and push, latest is only added when explicitly requested
+func imageTags(tag string, latest bool) ([]string, error) {
+	var tags []string
+	if tag != "" {
+		tags = append(tags, tag)
+	}
+	if latest && tag != "latest" {
+		tags = append(tags, "latest")
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("a --tag or --latest is required")
+	}
+	return tags, nil
+}
+
+// comingSoonTags removes latest from the tags of a coming soon repository so it is never published by default
+func comingSoonTags(name string, tags []string) ([]string, error) {
+	if !slices.Contains(tags, "latest") {
+		return tags, nil
+	}
+	if tags[0] == "latest" {
+		return nil, fmt.Errorf("repository %s is coming soon and can't be pushed to the latest tag", name)
+	}
+	log.Printf("Repository %s is coming soon, skipping the latest tag", name)
+	return slices.DeleteFunc(slices.Clone(tags), func(t string) bool { return t == "latest" }), nil
+}
+
+// imageLabels returns the labels marking the catalog status of the repository on its image
+func imageLabels(repository *hub.Repository) map[string]string {
+	labels := make(map[string]string)
+	if repository.Enterprise {
+		labels["hub.enterprise"] = "true"
+	}
+	if repository.ComingSoon {
+		labels["hub.coming-soon"] = "true"
+	}
+	if repository.License != "" {
+		labels["org.opencontainers.image.licenses"] = repository.License
+	}
+	// The build time would make the rebuilds of a commit differ
+	if !reproducible {
+		labels[docker.BuiltAtLabel] = time.Now().UTC().Format(time.RFC3339)
+	}
+	return labels
+}
+
+// imageArtifact returns the JSON of the catalog artifact of an image, labeled on the image so start --skip-build
+// runs it without cloning the repository
+func imageArtifact(name string, repository *hub.Repository, imageName string, cfg *smithery.SmitheryConfig) (string, error) {
+	c := catalog.Catalog{}
+	if err := c.Load(name, repository, imageName, cfg); err != nil {
+		return "", fmt.Errorf("load catalog: %w", err)
+	}
+	data, err := json.Marshal(c.Artifacts[0])
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// setupTempDirectory empties the clone and catalog directories, a resumed import keeps the catalogs of the
+// repositories imported by the previous run
 func setupTempDirectory() {
 	os.RemoveAll(tmpDir)
 	handleError("create temp directory", os.MkdirAll(tmpDir, 0755))
-	os.RemoveAll(catalog.CatalogDir)
+	if !resume {
+		os.RemoveAll(catalog.CatalogDir)
+	}
 	handleError("create catalog directory", os.MkdirAll(catalog.CatalogDir, 0755))
 }
 
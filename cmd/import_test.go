@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/blaxel-ai/mcp-hub/internal/docker"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+// fakeSteps returns import steps that fail the repositories of cloneErrors and buildErrors, a failing build waits
+// for the clone of the next repository
+func fakeSteps(names []string, cloneErrors, buildErrors map[string]bool) importSteps {
+	cloned := make(map[string]chan struct{})
+	for _, name := range names {
+		cloned[name] = make(chan struct{})
+	}
+	return importSteps{
+		prepare: func(name string, repository *hub.Repository, result *importResult) (*preparedRepository, error) {
+			if cloneErrors[name] {
+				return nil, errors.New("clone failed")
+			}
+			result.Cloned = true
+			close(cloned[name])
+			return &preparedRepository{name: name, repository: repository, cleanup: func() {}}, nil
+		},
+		build: func(p *preparedRepository, result *importResult) (*catalog.Catalog, error) {
+			if buildErrors[p.name] {
+				if i := slices.Index(names, p.name); i+1 < len(names) {
+					<-cloned[names[i+1]]
+				}
+				return nil, errors.New("build failed")
+			}
+			result.Built = true
+			return &catalog.Catalog{}, nil
+		},
+	}
+}
+
+func testHub(names ...string) *hub.Hub {
+	h := &hub.Hub{Repositories: map[string]*hub.Repository{}}
+	for _, name := range names {
+		h.Repositories[name] = &hub.Repository{}
+	}
+	return h
+}
+
+func testState(t *testing.T) *importState {
+	t.Helper()
+	state, err := newImportState(filepath.Join(t.TempDir(), importStateFile), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func setConcurrency(t *testing.T, clone, build int) {
+	t.Helper()
+	prevClone, prevBuild := cloneConcurrency, buildConcurrency
+	cloneConcurrency, buildConcurrency = clone, build
+	t.Cleanup(func() { cloneConcurrency, buildConcurrency = prevClone, prevBuild })
+}
+
+func TestImportRepositories(t *testing.T) {
+	tests := []struct {
+		name        string
+		cloneErrors map[string]bool
+		buildErrors map[string]bool
+		want        map[string]string
+		completed   []string
+	}{
+		{
+			name:      "all imported",
+			want:      map[string]string{"a": statusImported, "b": statusImported, "c": statusImported, "d": statusImported},
+			completed: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:        "clone failure skips the next repositories",
+			cloneErrors: map[string]bool{"a": true},
+			want:        map[string]string{"a": statusFailed, "b": statusSkipped, "c": statusSkipped, "d": statusSkipped},
+		},
+		{
+			// c is cloned while b builds, and holds the only clone slot until the build worker receives it
+			name:        "build failure aborts the waiting clone",
+			buildErrors: map[string]bool{"b": true},
+			want:        map[string]string{"a": statusImported, "b": statusFailed, "c": statusAborted, "d": statusSkipped},
+			completed:   []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConcurrency(t, 1, 1)
+			names := []string{"a", "b", "c", "d"}
+			state := testState(t)
+			results := importRepositories(testHub(names...), names, state, fakeSteps(names, tt.cloneErrors, tt.buildErrors))
+
+			if len(results) != len(names) {
+				t.Fatalf("got %d results, want one per repository", len(results))
+			}
+			for i, result := range results {
+				if result.Name != names[i] {
+					t.Errorf("result %d is %s, want %s", i, result.Name, names[i])
+				}
+				if result.Status != tt.want[result.Name] {
+					t.Errorf("%s status = %s, want %s", result.Name, result.Status, tt.want[result.Name])
+				}
+				if result.Status == statusAborted && !result.Cloned {
+					t.Errorf("aborted %s is not reported as cloned", result.Name)
+				}
+			}
+			if len(state.Completed) != len(tt.completed) {
+				t.Errorf("completed = %v, want %v", state.Completed, tt.completed)
+			}
+		})
+	}
+}
+
+// TestImportRepositoriesCloneSlots checks a clone holds its slot until a build worker receives it,
+// so at most cloneConcurrency clones wait for a build
+func TestImportRepositoriesCloneSlots(t *testing.T) {
+	setConcurrency(t, 2, 1)
+	names := []string{"a", "b", "c", "d", "e", "f"}
+
+	var mu sync.Mutex
+	prepared := 0
+	release := make(chan struct{})
+	steps := importSteps{
+		prepare: func(name string, repository *hub.Repository, result *importResult) (*preparedRepository, error) {
+			mu.Lock()
+			prepared++
+			mu.Unlock()
+			return &preparedRepository{name: name, cleanup: func() {}}, nil
+		},
+		build: func(p *preparedRepository, result *importResult) (*catalog.Catalog, error) {
+			<-release
+			return &catalog.Catalog{}, nil
+		},
+	}
+	state := testState(t)
+	done := make(chan []importResult)
+	go func() {
+		done <- importRepositories(testHub(names...), names, state, steps)
+	}()
+
+	// a is building, b and c wait for the build worker with both clone slots
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	if prepared != 3 {
+		t.Errorf("%d repositories cloned while the first one builds, want 3", prepared)
+	}
+	mu.Unlock()
+	close(release)
+
+	for _, result := range <-done {
+		if result.Status != statusImported {
+			t.Errorf("%s status = %s, want %s", result.Name, result.Status, statusImported)
+		}
+	}
+}
+
+func TestPushedDigestOfSkippedPush(t *testing.T) {
+	result := &importResult{remoteDigests: map[string]string{"ghcr.io/hub/brave:v1": "ghcr.io/hub/brave@sha256:abc"}}
+	ref, err := pushedDigest("ghcr.io/hub/brave:v1", result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref != "ghcr.io/hub/brave@sha256:abc" {
+		t.Errorf("ref = %s, want the digest read from the registry", ref)
+	}
+}
+
+func TestImageTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		explicit bool
+		latest   bool
+		push     bool
+		want     []string
+		wantErr  bool
+	}{
+		{name: "tag", tag: "v1", explicit: true, want: []string{"v1"}},
+		{name: "tag and latest", tag: "v1", explicit: true, latest: true, want: []string{"v1", "latest"}},
+		{name: "pushed tag", tag: "v1", explicit: true, push: true, want: []string{"v1"}},
+		{name: "pushed tag and latest", tag: "v1", explicit: true, latest: true, push: true, want: []string{"v1", "latest"}},
+		{name: "latest tag not repeated", tag: "latest", explicit: true, latest: true, want: []string{"latest"}},
+		{name: "local fallback", want: []string{"latest"}},
+		{name: "local fallback with latest", latest: true, want: []string{"latest"}},
+		{name: "push with latest only", latest: true, push: true, want: []string{"latest"}},
+		{name: "push without tag", push: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prevTag, prevLatest, prevPush := tag, latest, push
+			t.Cleanup(func() { tag, latest, push = prevTag, prevLatest, prevPush })
+			tag, latest, push = tt.tag, tt.latest, tt.push
+
+			// No VERSION file nor git tag in the config directory
+			resolved, err := resolveTag(tt.explicit, t.TempDir())
+			if err == nil {
+				var tags []string
+				tags, err = imageTags(resolved, latest)
+				if !slices.Equal(tags, tt.want) {
+					t.Errorf("tags = %v, want %v", tags, tt.want)
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrepareRepositoryMissingTemplate(t *testing.T) {
+	chdir(t, t.TempDir())
+	setFlag(t, &tag, "v1")
+	setFlag(t, &skipBuild, false)
+
+	// The repository can't be cloned, the template check must fail first
+	repository := &hub.Repository{Repository: "https://git.invalid/hub/brave.git", Branch: "main", Dockerfile: docker.HubDockerfile}
+	result := &importResult{Name: "brave"}
+	_, err := prepareRepository("brave", repository, result)
+	if err == nil || !strings.Contains(err.Error(), "missing Dockerfile template dockerfiles/brave.Dockerfile") {
+		t.Fatalf("err = %v, want the missing template", err)
+	}
+	if result.Cloned {
+		t.Error("repository cloned before the template check")
+	}
+}
+
+// smitheryFixture is the smithery config of the fixture repositories
+const smitheryFixture = `startCommand:
+  type: stdio
+  configSchema:
+    type: object
+    properties: {}
+  commandFunction: |-
+    config=>({command:'node',args:['index.js'],env:{}})
+`
+
+func TestPrepareRepositoryRefOverride(t *testing.T) {
+	chdir(t, t.TempDir())
+	setFlag(t, &tag, "v1")
+	setFlag(t, &skipBuild, true)
+	url, commits := gitFixture(t,
+		fixtureBranch{name: "main", files: map[string]string{"smithery.yaml": smitheryFixture}},
+		fixtureBranch{name: "fix-search", files: map[string]string{"index.js": "fixed"}},
+	)
+
+	tests := []struct {
+		name       string
+		branch     string
+		commit     string
+		wantBranch string
+		wantCommit string
+	}{
+		{name: "configured branch", wantBranch: "main", wantCommit: commits["main"]},
+		{name: "branch override", branch: "fix-search", wantBranch: "fix-search", wantCommit: commits["fix-search"]},
+		{name: "commit override", branch: "fix-search", commit: commits["main"], wantBranch: "fix-search", wantCommit: commits["main"]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &hub.Repository{Repository: url, Branch: "main", SmitheryPath: "smithery.yaml", Dockerfile: "Dockerfile"}
+			if err := repository.OverrideRef(tt.branch, tt.commit); err != nil {
+				t.Fatal(err)
+			}
+			p, err := prepareRepository("brave", repository, &importResult{Name: "brave"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.cleanup()
+			if p.source.Branch != tt.wantBranch || p.source.Commit != tt.wantCommit {
+				t.Errorf("cloned %s at %s, want %s at %s", p.source.Branch, p.source.Commit, tt.wantBranch, tt.wantCommit)
+			}
+		})
+	}
+}
+
+func TestImageLabels(t *testing.T) {
+	setFlag(t, &reproducible, true)
+	tests := []struct {
+		name       string
+		repository hub.Repository
+		want       map[string]string
+	}{
+		{name: "regular", repository: hub.Repository{}, want: map[string]string{}},
+		{name: "enterprise", repository: hub.Repository{Enterprise: true}, want: map[string]string{"hub.enterprise": "true"}},
+		{name: "coming soon", repository: hub.Repository{ComingSoon: true}, want: map[string]string{"hub.coming-soon": "true"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageLabels(&tt.repository); !maps.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComingSoonTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no latest", tags: []string{"v1"}, want: []string{"v1"}},
+		{name: "latest dropped", tags: []string{"v1", "latest"}, want: []string{"v1"}},
+		{name: "only latest", tags: []string{"latest"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := comingSoonTags("brave", tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareRepositoryCleanup(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	setFlag(t, &tag, "v1")
+	setFlag(t, &skipBuild, true)
+	url, _ := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"smithery.yaml": smitheryFixture}})
+	local := filepath.Join(dir, "servers", "brave")
+	writeFiles(t, local, map[string]string{"index.js": "console.log('brave')", "smithery.yaml": smitheryFixture})
+
+	// The failure runs the cleanup
+	repository := &hub.Repository{Path: local, SmitheryPath: "missing.yaml"}
+	if _, err := prepareRepository("brave", repository, &importResult{Name: "brave"}); err == nil {
+		t.Fatal("expected an error without a smithery file")
+	}
+	p, err := prepareRepository("brave", &hub.Repository{Path: local, SmitheryPath: "smithery.yaml"}, &importResult{Name: "brave"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.cleanup()
+	if _, err := os.Stat(filepath.Join(local, "index.js")); err != nil {
+		t.Errorf("local path removed: %v", err)
+	}
+
+	p, err = prepareRepository("slack", &hub.Repository{Repository: url, Branch: "main", SmitheryPath: "smithery.yaml"}, &importResult{Name: "slack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(p.repoPath, tmpDir+string(filepath.Separator)) {
+		t.Fatalf("cloned to %s, want a path in the cache", p.repoPath)
+	}
+	p.cleanup()
+	if _, err := os.Stat(p.repoPath); !os.IsNotExist(err) {
+		t.Errorf("clone %s not removed: %v", p.repoPath, err)
+	}
+	if _, err := os.Stat(tmpDir); err != nil {
+		t.Errorf("clone cache removed: %v", err)
+	}
+}
+
+func TestPrepareRepositorySmitheryFile(t *testing.T) {
+	chdir(t, t.TempDir())
+	setFlag(t, &tag, "v1")
+	setFlag(t, &skipBuild, true)
+	local := t.TempDir()
+	writeFiles(t, local, map[string]string{"malformed.yaml": "startCommand: [stdio\n"})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "missing.yaml", want: `no smithery file at "missing.yaml" in repository brave, set smitheryPath to its location or provide an inline smithery config`},
+		{path: "malformed.yaml", want: "parse smithery file: malformed smithery file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := prepareRepository("brave", &hub.Repository{Path: local, SmitheryPath: tt.path}, &importResult{Name: "brave"})
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessRepositorySource(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	setFlag(t, &tag, "v1")
+	setFlag(t, &skipBuild, true)
+	setFlag(t, &debug, true)
+	url, commits := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"smithery.yaml": smitheryFixture}})
+	local := filepath.Join(dir, "servers", "brave")
+	writeFiles(t, local, map[string]string{"smithery.yaml": smitheryFixture})
+
+	c, err := processRepository("slack", &hub.Repository{Repository: url, Branch: "main", SmitheryPath: "smithery.yaml"}, &importResult{Name: "slack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := c.Artifacts[0].Source
+	if source == nil || source.Commit != commits["main"] || source.Branch != "main" || source.Repository != url || source.ClonedAt == nil {
+		t.Errorf("got source %+v, want the commit %s of the clone", source, commits["main"])
+	}
+	output, err := json.Marshal(c.Artifacts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), `"commit":"`+commits["main"]+`"`) {
+		t.Errorf("catalog %s has no commit", output)
+	}
+
+	c, err = processRepository("brave", &hub.Repository{Path: local, SmitheryPath: "smithery.yaml"}, &importResult{Name: "brave"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := catalog.Source{Path: local, Note: "built from a local path, no commit is available"}
+	if source := c.Artifacts[0].Source; source == nil || *source != want {
+		t.Errorf("got source %+v, want %+v", source, want)
+	}
+}
+
+func TestSelectRepositories(t *testing.T) {
+	h := &hub.Hub{
+		Repositories: map[string]*hub.Repository{
+			"brave":   {},
+			"grafana": {},
+			"sentry":  {},
+			"legacy":  {Disabled: true},
+		},
+		Groups: map[string][]string{"observability": {"sentry", "grafana", "legacy"}},
+	}
+	tests := []struct {
+		name    string
+		mcp     string
+		group   string
+		changed []string
+		want    []string
+		wantErr string
+	}{
+		{name: "every repository", want: []string{"brave", "grafana", "sentry"}},
+		{name: "mcp", mcp: "brave", want: []string{"brave"}},
+		{name: "group", group: "observability", want: []string{"grafana", "sentry"}},
+		{name: "union of group and mcp", mcp: "brave", group: "observability", want: []string{"brave", "grafana", "sentry"}},
+		{name: "group limited to changed", group: "observability", changed: []string{"sentry", "brave"}, want: []string{"sentry"}},
+		{name: "unknown group", group: "database", wantErr: "group database not found in _groups.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(t, &mcp, tt.mcp)
+			setFlag(t, &group, tt.group)
+			got, err := selectRepositories(h, tt.changed)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageSizeLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       string
+		repository hub.Repository
+		want       int64
+	}{
+		{name: "no limit"},
+		{name: "flag", flag: "1g", want: 1 << 30},
+		{name: "repository", repository: hub.Repository{MaxImageSize: "300m"}, want: 300 << 20},
+		{name: "repository over the flag", flag: "1g", repository: hub.Repository{MaxImageSize: "2g"}, want: 2 << 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(t, &maxImageSize, tt.flag)
+			if got := imageSizeLimit(&tt.repository); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/blaxel-ai/mcp-hub/internal/mcpclient"
+	"github.com/blaxel-ai/mcp-hub/internal/secrets"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	toolName      string
+	toolArgs      string
+	invokeTimeout time.Duration
+)
+
+var invokeCmd = &cobra.Command{
+	Use:   "invoke",
+	Short: "Call a tool of a MCP server",
+	Long:  `invoke is a CLI tool to build & start a MCP server, call one of its tools with JSON arguments and print the result`,
+	Run:   runInvoke,
+}
+
+func init() {
+	invokeCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	invokeCmd.Flags().StringVarP(&registry, "registry", "r", "ghcr.io/blaxel-ai/hub", "The registry the image is built for")
+	invokeCmd.Flags().StringVarP(&mcp, "mcp", "m", "", "The MCP to invoke")
+	invokeCmd.Flags().StringVar(&toolName, "tool", "", "The name of the tool to call")
+	invokeCmd.Flags().StringVar(&toolArgs, "args", "{}", "The arguments of the tool as a JSON object")
+	invokeCmd.Flags().DurationVar(&invokeTimeout, "timeout", 2*time.Minute, "The maximum time to start the server and call the tool")
+	invokeCmd.Flags().StringVar(&branch, "branch", "", "Override the branch of the MCP repository")
+	invokeCmd.Flags().StringVar(&commit, "commit", "", "Override the commit of the MCP repository, takes precedence over the branch head")
+	invokeCmd.Flags().BoolVarP(&skipBuild, "skip-build", "s", false, "Reuse the image built before instead of building it")
+	invokeCmd.Flags().StringVarP(&tag, "tag", "t", "", "The tag to use for the image, defaults to the VERSION file of the config, git describe or latest")
+	invokeCmd.Flags().StringVar(&proxy, "proxy", "", "The proxy to use for clone and build, defaults to the HTTP_PROXY and HTTPS_PROXY environment variables")
+	invokeCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	invokeCmd.Flags().StringVar(&secretProvider, "secret-provider", secrets.ProviderEnv, "The provider to read the MCP secrets from (env, vault)")
+	rootCmd.AddCommand(invokeCmd)
+}
+
+func runInvoke(cmd *cobra.Command, args []string) {
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: No .env file found or error loading it: %v", err)
+	}
+
+	if configPath == "" {
+		configPath = "hub"
+	}
+	if mcp == "" || toolName == "" {
+		log.Printf("MCP and tool are required")
+		os.Exit(1)
+	}
+	var arguments map[string]any
+	if err := json.Unmarshal([]byte(toolArgs), &arguments); err != nil {
+		log.Printf("Invalid tool arguments, expected a JSON object: %v", err)
+		os.Exit(1)
+	}
+
+	repository, artifact, envValues := loadMCP(cmd)
+	handleError("validate run options", repository.Run.Validate())
+	result, err := invokeTool(artifact, envValues, repository.Run, arguments)
+	if err != nil {
+		log.Printf("Failed to invoke tool %s of %s: %v", toolName, mcp, err)
+		os.Exit(1)
+	}
+	output, err := json.MarshalIndent(result, "", "  ")
+	handleError("encode tool result", err)
+	fmt.Println(string(output))
+	if result.IsError {
+		os.Exit(1)
+	}
+}
+
+// invokeTool runs the MCP server of the image over stdio, without its gateway, and calls the tool
+func invokeTool(artifact catalog.Artifact, envValues map[string]string, run hub.Run, arguments map[string]any) (*mcpclient.ToolResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), invokeTimeout)
+	defer cancel()
+
+	name := containerName("mcp-hub-invoke")
+	exec.Command("docker", "rm", "-f", name).Run()
+
+	dockerRunCmd := []string{"run", "--rm", "-i", "--name", name}
+	dockerRunCmd = append(dockerRunCmd, runOptionArgs(envValues, run)...)
+	dockerRunCmd = append(dockerRunCmd, "--entrypoint", artifact.Entrypoint.Command, artifact.Image)
+	dockerRunCmd = append(dockerRunCmd, artifact.Entrypoint.Args...)
+	cmd := exec.CommandContext(ctx, "docker", dockerRunCmd...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Servers don't all exit on the end of their input
+		stdin.Close()
+		exec.Command("docker", "rm", "-f", name).Run()
+		cmd.Wait()
+	}()
+
+	client := mcpclient.NewClient(stdin, stdout)
+	if err := client.Initialize("mcp-hub", tag); err != nil {
+		return nil, withTimeout(ctx, err)
+	}
+	result, err := client.CallTool(toolName, arguments)
+	return result, withTimeout(ctx, err)
+}
+
+// withTimeout reports the timeout rather than the closed output of the killed server
+func withTimeout(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("no response within %s: %w", invokeTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+// fakeMCPDocker is a docker whose run answers the MCP handshake and calls of the search tool on stdio,
+// it logs the requests to the $REQUESTS file
+const fakeMCPDocker = `case "$1" in
+run) while read -r line; do
+  echo "$line" >> "$REQUESTS"
+  id=$(echo "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  case "$line" in
+  *'"method":"initialize"'*) echo "brave search server running on stdio"; echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{\"protocolVersion\":\"2024-11-05\",\"capabilities\":{}}}" ;;
+  *'"name":"search"'*) echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{\"content\":[{\"type\":\"text\",\"text\":\"3 results\"}]}}" ;;
+  *'"method":"tools/call"'*) echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"error\":{\"code\":-32602,\"message\":\"Unknown tool\"}}" ;;
+  esac
+done ;;
+esac`
+
+func TestInvokeTool(t *testing.T) {
+	setFlag(t, &tag, "v1")
+	requests := filepath.Join(t.TempDir(), "requests.log")
+	t.Setenv("REQUESTS", requests)
+	dockerLog := fakeTool(t, "docker", fakeMCPDocker)
+
+	setFlag(t, &toolName, "search")
+	result, err := invokeTool(testArtifact(), map[string]string{"API_KEY": "secret"}, hub.Run{}, map[string]any{"query": "mcp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Content) != 1 || string(result.Content[0]) != `{"type":"text","text":"3 results"}` || result.IsError {
+		t.Errorf("got %+v, want the known result", result)
+	}
+	logged := toolCalls(t, requests)
+	methods := []string{`"method":"initialize"`, `"method":"notifications/initialized"`, `"arguments":{"query":"mcp"}`}
+	if len(logged) != len(methods) {
+		t.Fatalf("requests %q, want the handshake and the call", logged)
+	}
+	for i, method := range methods {
+		if !strings.Contains(logged[i], method) {
+			t.Errorf("request %d = %s, want %s", i, logged[i], method)
+		}
+	}
+	calls := toolCalls(t, dockerLog)
+	run := slices.IndexFunc(calls, func(call string) bool { return strings.HasPrefix(call, "run ") })
+	if run < 0 || !strings.HasSuffix(calls[run], "-e API_KEY=secret --entrypoint node ghcr.io/hub/brave:v1 dist/index.js") {
+		t.Errorf("docker calls %q, want the run of the image entrypoint", calls)
+	}
+	if last := calls[len(calls)-1]; !strings.HasPrefix(last, "rm -f mcp-hub-invoke") {
+		t.Errorf("last docker call %q, want the container removed", last)
+	}
+
+	setFlag(t, &toolName, "fetch")
+	if _, err := invokeTool(testArtifact(), map[string]string{"API_KEY": "secret"}, hub.Run{}, nil); err == nil || err.Error() != "call tool fetch: error -32602: Unknown tool" {
+		t.Errorf("err = %v, want the unknown tool", err)
+	}
+}
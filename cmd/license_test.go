@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+func TestCheckLicenses(t *testing.T) {
+	setFlag(t, &allowLicenses, []string{"MIT", "Apache-2.0"})
+	h := &hub.Hub{Repositories: map[string]*hub.Repository{
+		"brave":  {License: "MIT"},
+		"exa":    {License: "MIT OR GPL-3.0-only"},
+		"notion": {License: "GPL-3.0-only"},
+		"slack":  {License: "Apache-2.0 AND AGPL-3.0-only"},
+	}}
+	if err := checkLicenses(h, []string{"brave", "exa"}); err != nil {
+		t.Errorf("err = %v, want the allowed licenses accepted", err)
+	}
+	want := "repository notion license GPL-3.0-only is not allowed\n" +
+		"repository slack license Apache-2.0 AND AGPL-3.0-only is not allowed"
+	if err := checkLicenses(h, []string{"brave", "notion", "slack"}); err == nil || err.Error() != want {
+		t.Errorf("got\n%v\nwant\n%s", err, want)
+	}
+}
+
+func TestImportAllowLicenses(t *testing.T) {
+	tests := []struct {
+		name    string
+		license string
+		allow   string
+		code    int
+		stderr  string
+	}{
+		{name: "allowed", license: "MIT", allow: "MIT,Apache-2.0"},
+		{name: "one license of an OR allowed", license: "GPL-3.0-only OR MIT", allow: "MIT"},
+		{name: "not allowed", license: "GPL-3.0-only", allow: "MIT,Apache-2.0", code: 1, stderr: "repository brave license GPL-3.0-only is not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := strings.Replace(fmt.Sprintf(braveConfig, filepath.Join(dir, "src")), "license: MIT", "license: "+tt.license, 1)
+			writeFiles(t, dir, map[string]string{"src/Dockerfile": "FROM node:22-alpine\n", "hub/brave.yaml": config})
+			result := runCLI(t, dir, nil, "import", "-c", "hub", "--skip-build", "-d", "--tag", "v1", "--allow-licenses", tt.allow)
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			if !strings.Contains(result.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", result.stderr, tt.stderr)
+			}
+		})
+	}
+}
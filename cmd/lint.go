@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/blaxel-ai/mcp-hub/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var strict bool
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Lint the hub configuration",
+	Long:  `lint is a CLI tool to report missing recommended fields in the hub configuration, errors are fatal and warnings are not`,
+	Run:   runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	lintCmd.Flags().IntVar(&maxLongDescription, "max-long-description", 2000, "The maximum length of a long description, 0 to disable the check")
+	lintCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	lintCmd.Flags().StringVar(&vocabularyPath, "vocabulary", "", "The file listing the allowed tags and categories, not checked when empty")
+	lintCmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors")
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLint(cmd *cobra.Command, args []string) {
+	if configPath == "" {
+		configPath = "hub"
+	}
+
+	hub, err := readHub()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, issue := range lint.Lint(hub) {
+		fmt.Println(issue)
+		if issue.Severity == lint.SeverityError || strict {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintStrict(t *testing.T) {
+	dir := testConfig(t)
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{name: "warnings are not fatal", args: []string{"lint", "-c", "hub"}},
+		{name: "strict fails on warnings", args: []string{"lint", "-c", "hub", "--strict"}, code: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCLI(t, dir, nil, tt.args...)
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			want := "warning: brave: longDescription is the same as description (long-description-length)\nwarning: brave: no tags (tags)\n"
+			if result.stdout != want {
+				t.Errorf("stdout = %q, want %q", result.stdout, want)
+			}
+		})
+	}
+
+	writeFiles(t, dir, map[string]string{"hub/broken.yaml": "displayName: Broken\n"})
+	result := runCLI(t, dir, nil, "lint", "-c", "hub")
+	if result.code != 1 || !strings.HasPrefix(result.stderr, "error: ") {
+		t.Errorf("exit code %d, stderr %q, want the failed validation", result.code, result.stderr)
+	}
+}
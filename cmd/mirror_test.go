@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/git"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestCloneRepositoryMirror(t *testing.T) {
+	chdir(t, t.TempDir())
+	mirrorDir := t.TempDir()
+	setFlag(t, &mirrorRemote, "file://"+mirrorDir)
+	upstream, commits := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"index.js": "console.log('brave')"}})
+	// A host in the URL gives the repository a path in the mirror
+	repository := &hub.Repository{Repository: "file://localhost" + upstream, Branch: "main"}
+	mirrorPath := filepath.Join(mirrorDir, "localhost", upstream)
+	mirror, err := gogit.PlainInit(mirrorPath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mirror doesn't have the branch yet, the repository is cloned and pushed to it
+	repoPath := filepath.Join(tmpDir, "brave", "main")
+	if err := cloneRepository(repoPath, repository); err != nil {
+		t.Fatal(err)
+	}
+	ref, err := mirror.Reference(plumbing.NewBranchReferenceName("main"), false)
+	if err != nil || ref.Hash().String() != commits["main"] {
+		t.Fatalf("mirror branch %v, %v, want %s", ref, err, commits["main"])
+	}
+
+	// Once mirrored, the clone no longer needs the repository
+	if err := os.RemoveAll(upstream); err != nil {
+		t.Fatal(err)
+	}
+	repoPath = filepath.Join(tmpDir, "brave-again", "main")
+	if err := cloneRepository(repoPath, repository); err != nil {
+		t.Fatal(err)
+	}
+	if commit, err := git.HeadCommit(repoPath); err != nil || commit != commits["main"] {
+		t.Errorf("cloned %s, %v, want %s from the mirror", commit, err, commits["main"])
+	}
+}
+
+func TestCloneRepositoryMirrorUnreachable(t *testing.T) {
+	chdir(t, t.TempDir())
+	setFlag(t, &mirrorRemote, "file://"+filepath.Join(t.TempDir(), "missing"))
+	upstream, commits := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"index.js": "console.log('brave')"}})
+
+	// Neither the clone nor the push can use the mirror, the import goes on from the repository
+	repoPath := filepath.Join(tmpDir, "brave", "main")
+	if err := cloneRepository(repoPath, &hub.Repository{Repository: "file://localhost" + upstream, Branch: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	if commit, err := git.HeadCommit(repoPath); err != nil || commit != commits["main"] {
+		t.Errorf("cloned %s, %v, want %s", commit, err, commits["main"])
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/blaxel-ai/mcp-hub/internal/docker"
+	"github.com/spf13/cobra"
+)
+
+var (
+	olderThan     time.Duration
+	pruneDangling bool
+	force         bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the local images built by the hub",
+	Long:  `prune is a CLI tool to remove the local images of the hub repositories older than a given age, it only lists them unless --force is set`,
+	Run:   runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
+	pruneCmd.Flags().StringVarP(&registry, "registry", "r", "ghcr.io/blaxel-ai/hub", "The registry the images were built for")
+	pruneCmd.Flags().StringVarP(&mcp, "mcp", "m", "", "The MCP to prune the images of, if not provided, the images of all MCPs are pruned")
+	pruneCmd.Flags().DurationVar(&olderThan, "older-than", 7*24*time.Hour, "Only remove the images built before this age")
+	pruneCmd.Flags().BoolVar(&pruneDangling, "dangling", false, "Also remove the dangling images with docker image prune")
+	pruneCmd.Flags().BoolVar(&force, "force", false, "Remove the images, they are only listed otherwise")
+	pruneCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	rootCmd.AddCommand(pruneCmd)
+}
+
+func runPrune(cmd *cobra.Command, args []string) {
+	if configPath == "" {
+		configPath = "hub"
+	}
+
+	hub, err := readHub()
+	handleError("load config", err)
+
+	ctx := context.Background()
+	before := time.Now().Add(-olderThan)
+	for _, name := range slices.Sorted(maps.Keys(hub.Repositories)) {
+		if mcp != "" && mcp != name {
+			continue
+		}
+		images, err := docker.ListImages(ctx, fmt.Sprintf("%s/%s", strings.ToLower(registry), strings.ToLower(name)))
+		handleError("list images", err)
+		for _, image := range images {
+			if !image.BuiltAt.Before(before) {
+				continue
+			}
+			if !force {
+				log.Printf("Would remove %s built %s", image.Ref(), image.BuiltAt.Format(time.RFC3339))
+				continue
+			}
+			if err := docker.RemoveImage(ctx, image.Ref()); err != nil {
+				log.Printf("Failed to remove %s: %v", image.Ref(), err)
+				continue
+			}
+			log.Printf("Removed %s", image.Ref())
+		}
+	}
+
+	if !force {
+		if pruneDangling {
+			log.Printf("Would remove the dangling images")
+		}
+		log.Printf("Nothing removed, run with --force to remove the images")
+		return
+	}
+	if pruneDangling {
+		handleError("prune dangling images", docker.PruneDanglingImages(ctx))
+	}
+}
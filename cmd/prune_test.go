@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakePruneDocker lists an old image of brave, dated by its creation as it has no build label nor tag time, and a
+// recent one
+const fakePruneDocker = `case "$1 $2" in
+"image ls")
+	if [ "$5" = registry.test/brave ]; then
+		printf 'registry.test/brave\tv1\tsha-old\t2024-03-01 12:00:00 +0000 UTC\n'
+		printf 'registry.test/brave\tv2\tsha-new\t2024-03-01 12:00:00 +0000 UTC\n'
+	fi
+	;;
+"image inspect")
+	if [ "$5" = sha-new ]; then
+		printf '2999-01-01T00:00:00Z\t0001-01-01 00:00:00 +0000 UTC\n'
+	else
+		printf '<no value>\t0001-01-01 00:00:00 +0000 UTC\n'
+	fi
+	;;
+esac`
+
+func TestPrune(t *testing.T) {
+	dir := testConfig(t)
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "dry run", want: nil},
+		{name: "force", args: []string{"--force"}, want: []string{"image rm registry.test/brave:v1"}},
+		{name: "force with dangling images", args: []string{"--force", "--dangling"}, want: []string{"image rm registry.test/brave:v1", "image prune --force"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerLog := fakeTool(t, "docker", fakePruneDocker)
+			result := runCLI(t, dir, nil, append([]string{"prune", "-c", "hub", "-r", "registry.test"}, tt.args...)...)
+			if result.code != 0 {
+				t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+			}
+			var listed, removed []string
+			for _, call := range toolCalls(t, dockerLog) {
+				switch {
+				case strings.HasPrefix(call, "image ls "):
+					listed = append(listed, call[strings.LastIndex(call, " ")+1:])
+				case strings.HasPrefix(call, "image rm "), strings.HasPrefix(call, "image prune "):
+					removed = append(removed, call)
+				}
+			}
+			if !slices.Equal(listed, []string{"registry.test/brave"}) {
+				t.Errorf("listed %v, want only the images of the hub repositories", listed)
+			}
+			if !slices.Equal(removed, tt.want) {
+				t.Errorf("removed %v, want %v", removed, tt.want)
+			}
+			if tt.want == nil && !strings.Contains(result.stderr, "Would remove registry.test/brave:v1 built 2024-03-01T12:00:00Z") {
+				t.Errorf("stderr = %q, want the image listed", result.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImportSkipsPushedImages(t *testing.T) {
+	dir := testConfig(t)
+	tests := []struct {
+		name     string
+		manifest string
+		args     []string
+		want     []string
+	}{
+		{
+			name:     "manifest present",
+			manifest: `echo '{"Descriptor": {"digest": "sha256:manifest"}, "SchemaV2Manifest": {"config": {"digest": "sha256:config"}}}'`,
+		},
+		{
+			name:     "manifest absent",
+			manifest: `echo "no such manifest: registry.test/brave:v1" >&2; exit 1`,
+			want:     []string{"push registry.test/brave:v1"},
+		},
+		{
+			name:     "other image under the tag",
+			manifest: `echo '{"Descriptor": {"digest": "sha256:other"}, "SchemaV2Manifest": {"config": {"digest": "sha256:older"}}}'`,
+			want:     []string{"push registry.test/brave:v1"},
+		},
+		{
+			name:     "registry unreachable",
+			manifest: `echo "connection refused" >&2; exit 1`,
+			want:     []string{"push registry.test/brave:v1"},
+		},
+		{
+			name:     "forced push",
+			manifest: `echo '{"Descriptor": {"digest": "sha256:manifest"}, "SchemaV2Manifest": {"config": {"digest": "sha256:config"}}}'`,
+			args:     []string{"--force-push"},
+			want:     []string{"push registry.test/brave:v1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerLog := fakeTool(t, "docker", `case "$1" in
+image) echo sha256:config ;;
+manifest) `+tt.manifest+` ;;
+esac`)
+			args := append([]string{"import", "-c", "hub", "-d", "--push", "--tag", "v1", "--registry", "registry.test"}, tt.args...)
+			result := runCLI(t, dir, nil, args...)
+			if result.code != 0 {
+				t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+			}
+			var pushes []string
+			for _, call := range toolCalls(t, dockerLog) {
+				if strings.HasPrefix(call, "push ") {
+					pushes = append(pushes, call)
+				}
+			}
+			if !slices.Equal(pushes, tt.want) {
+				t.Errorf("pushes %q, want %q", pushes, tt.want)
+			}
+			skipped := strings.Contains(result.stderr, "Skipping push of registry.test/brave:v1, the registry already has this image")
+			if skipped != (tt.want == nil) {
+				t.Errorf("skip reported %t, stderr:\n%s", skipped, result.stderr)
+			}
+		})
+	}
+}
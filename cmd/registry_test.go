@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+)
+
+// fakeRegistryDocker is a docker whose local image is already in the registry
+const fakeRegistryDocker = `case "$1" in
+image) echo sha256:config ;;
+manifest) echo '{"Descriptor": {"digest": "sha256:manifest"}, "SchemaV2Manifest": {"config": {"digest": "sha256:config"}}}' ;;
+esac`
+
+func TestRegistryInsecurePlumbing(t *testing.T) {
+	for _, insecure := range []bool{true, false} {
+		t.Run(map[bool]string{true: "insecure", false: "secure"}[insecure], func(t *testing.T) {
+			setFlag(t, &registryInsecure, insecure)
+			dockerLog := fakeTool(t, "docker", fakeRegistryDocker)
+			orasLog := fakeTool(t, "oras", "")
+
+			imageName := "localhost:5000/hub/brave:v1"
+			ref := imagePushed(imageName)
+			if ref != "localhost:5000/hub/brave@sha256:manifest" {
+				t.Fatalf("ref = %s, want the registry digest", ref)
+			}
+			result := &importResult{remoteDigests: map[string]string{imageName: ref}}
+			c := &catalog.Catalog{Artifacts: []catalog.Artifact{{Name: "brave"}}}
+			if err := attachCatalog(c, imageName, result); err != nil {
+				t.Fatal(err)
+			}
+
+			calls := map[string]string{
+				"--insecure":   strings.Join(toolCalls(t, dockerLog), "\n"),
+				"--plain-http": strings.Join(toolCalls(t, orasLog), "\n"),
+			}
+			for flag, call := range calls {
+				if strings.Contains(call, flag) != insecure {
+					t.Errorf("calls %q, want %s %v", call, flag, insecure)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	huberrors "github.com/blaxel-ai/mcp-hub/internal/errors"
+)
+
+const (
+	statusImported = "imported"
+	statusFailed   = "failed"
+	// statusAborted is a repository cloned but not built as another repository failed
+	statusAborted = "aborted"
+	// statusSkipped is a repository not started as another repository failed
+	statusSkipped = "skipped"
+)
+
+// importResult is the outcome of the import of a repository, written to the --report-out file
+type importResult struct {
+	Name string `json:"name"`
+	// Status is imported, failed, aborted or skipped
+	Status   string  `json:"status"`
+	Cloned   bool    `json:"cloned"`
+	Built    bool    `json:"built"`
+	Pushed   bool    `json:"pushed"`
+	Duration float64 `json:"duration"`
+	Error    string  `json:"error,omitempty"`
+	// ErrorKind is the stage that failed: clone, build, push or validation
+	ErrorKind string `json:"errorKind,omitempty"`
+
+	// SkippedPushes are the images not pushed as the registry already had them
+	SkippedPushes []string `json:"skippedPushes,omitempty"`
+
+	// remoteDigests are the digest references of the skipped pushes, read from the registry as the local
+	// images have no repository digest
+	remoteDigests map[string]string
+}
+
+func (r *importResult) finish(start time.Time, err error) {
+	r.Duration = time.Since(start).Seconds()
+	r.Status = statusImported
+	if err != nil {
+		r.Status = statusFailed
+		r.Error = err.Error()
+		r.ErrorKind = errorKind(err)
+	}
+}
+
+// errorKind returns the stage of the import an error comes from, empty when unknown
+func errorKind(err error) string {
+	var cloneErr *huberrors.CloneError
+	var buildErr *huberrors.BuildError
+	var pushErr *huberrors.PushError
+	var validationErr *huberrors.ValidationError
+	switch {
+	case errors.As(err, &cloneErr):
+		return "clone"
+	case errors.As(err, &buildErr):
+		return "build"
+	case errors.As(err, &pushErr):
+		return "push"
+	case errors.As(err, &validationErr):
+		return "validation"
+	default:
+		return ""
+	}
+}
+
+func writeReport(path string, results []importResult) error {
+	if results == nil {
+		results = []importResult{}
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
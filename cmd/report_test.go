@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportReport(t *testing.T) {
+	dir := testConfig(t)
+	// asana is imported first, its command function doesn't parse
+	asana := strings.Replace(fmt.Sprintf(braveConfig, filepath.Join(dir, "src")), "config=>(", "config=>((", 1)
+	writeFiles(t, dir, map[string]string{"hub/asana.yaml": asana})
+	tests := []struct {
+		name string
+		args []string
+		code int
+		want map[string]string
+	}{
+		{
+			name: "selected repository",
+			args: []string{"-m", "brave"},
+			want: map[string]string{"brave": statusImported},
+		},
+		{
+			name: "failed repository",
+			args: []string{"--clone-concurrency", "1"},
+			code: 1,
+			want: map[string]string{"asana": statusFailed, "brave": statusSkipped},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"import", "-c", "hub", "--skip-build", "-d", "--tag", "v1", "--report-out", "report.json"}, tt.args...)
+			result := runCLI(t, dir, nil, args...)
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "report.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var results []importResult
+			if err := json.Unmarshal(data, &results); err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("got %d entries, want one per selected repository:\n%s", len(results), data)
+			}
+			for _, result := range results {
+				if result.Status != tt.want[result.Name] {
+					t.Errorf("%s status = %s, want %s", result.Name, result.Status, tt.want[result.Name])
+				}
+				if result.Built || result.Pushed {
+					t.Errorf("%s reported built or pushed with --skip-build", result.Name)
+				}
+				if (result.Status == statusFailed) != (result.Error != "") {
+					t.Errorf("%s error = %q with status %s", result.Name, result.Error, result.Status)
+				}
+			}
+		})
+	}
+}
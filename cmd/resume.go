@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// importStateFile is the default path of the progress of the import read by --resume
+const importStateFile = ".import-state.yaml"
+
+// importState is the progress of an import, the repositories imported successfully with its tag and registry
+type importState struct {
+	Tag       string   `yaml:"tag"`
+	Registry  string   `yaml:"registry"`
+	Completed []string `yaml:"completed"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// newImportState returns the state of the import, the one of the previous run when resuming an import of the
+// same tag and registry
+func newImportState(path string, resume bool) (*importState, error) {
+	state := &importState{Tag: tag, Registry: registry, path: path}
+	if !resume {
+		return state, nil
+	}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Printf("No import state found at %s, importing all repositories", path)
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var previous importState
+	if err := yaml.UnmarshalStrict(content, &previous); err != nil {
+		return nil, fmt.Errorf("parse import state %s: %w", path, err)
+	}
+	if previous.Tag != tag || previous.Registry != registry {
+		log.Printf("Import state %s is for %s:%s, importing all repositories", path, previous.Registry, previous.Tag)
+		return state, nil
+	}
+	state.Completed = previous.Completed
+	return state, nil
+}
+
+// pending returns the names not completed by the previous run
+func (s *importState) pending(names []string) []string {
+	var pending []string
+	for _, name := range names {
+		if !slices.Contains(s.Completed, name) {
+			pending = append(pending, name)
+		}
+	}
+	if skipped := len(names) - len(pending); skipped > 0 {
+		log.Printf("Skipping %d repositories imported by the previous run", skipped)
+	}
+	return pending
+}
+
+// complete records a repository imported successfully, the state is written right away to survive a crash
+func (s *importState) complete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Completed = append(s.Completed, name)
+	slices.Sort(s.Completed)
+	content, err := yaml.Marshal(s)
+	if err == nil {
+		err = os.WriteFile(s.path, content, 0644)
+	}
+	if err != nil {
+		log.Printf("Warning: failed to write import state %s: %v", s.path, err)
+	}
+}
+
+// clear removes the state once every repository is imported
+func (s *importState) clear() {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: failed to remove import state %s: %v", s.path, err)
+	}
+}
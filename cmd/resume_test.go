@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+// chdir runs the test in dir, the import works in the current directory
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestImportResume(t *testing.T) {
+	chdir(t, t.TempDir())
+	setConcurrency(t, 1, 1)
+	prevTag, prevRegistry, prevResume := tag, registry, resume
+	t.Cleanup(func() { tag, registry, resume = prevTag, prevRegistry, prevResume })
+	tag, registry = "v1", "registry.test"
+
+	names := []string{"a", "b", "c"}
+	h := testHub(names...)
+	var mu sync.Mutex
+	var prepared []string
+	steps := func(failing string) importSteps {
+		store := &catalog.FileStore{Dir: catalog.CatalogDir}
+		return importSteps{
+			prepare: func(name string, repository *hub.Repository, result *importResult) (*preparedRepository, error) {
+				mu.Lock()
+				prepared = append(prepared, name)
+				mu.Unlock()
+				return &preparedRepository{name: name, cleanup: func() {}}, nil
+			},
+			build: func(p *preparedRepository, result *importResult) (*catalog.Catalog, error) {
+				if p.name == failing {
+					return nil, errors.New("build failed")
+				}
+				return &catalog.Catalog{}, store.Put(p.name, []byte("{}"))
+			},
+		}
+	}
+	run := func(failing string) []importResult {
+		t.Helper()
+		setupTempDirectory()
+		state, err := newImportState(importStateFile, resume)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := importRepositories(h, state.pending(names), state, steps(failing))
+		if !slices.ContainsFunc(results, func(r importResult) bool { return r.Error != "" }) {
+			state.clear()
+		}
+		return results
+	}
+
+	// The first run fails on b, a is imported
+	run("b")
+	prepared = nil
+
+	resume = true
+	results := run("")
+	if !slices.Equal(prepared, []string{"b", "c"}) {
+		t.Errorf("resumed run imported %v, want [b c]", prepared)
+	}
+	for _, result := range results {
+		if result.Status != statusImported {
+			t.Errorf("%s status = %s, want %s", result.Name, result.Status, statusImported)
+		}
+	}
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(catalog.CatalogDir, name+".json")); err != nil {
+			t.Errorf("catalog of %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(importStateFile); !os.IsNotExist(err) {
+		t.Errorf("import state not removed after a full success: %v", err)
+	}
+}
+
+func TestImportStateOtherTag(t *testing.T) {
+	prevTag, prevRegistry := tag, registry
+	t.Cleanup(func() { tag, registry = prevTag, prevRegistry })
+	tag, registry = "v1", "registry.test"
+	path := filepath.Join(t.TempDir(), importStateFile)
+
+	state, err := newImportState(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.complete("a")
+
+	tests := []struct {
+		name string
+		tag  string
+		want []string
+	}{
+		{name: "same tag", tag: "v1", want: []string{"b"}},
+		{name: "other tag", tag: "v2", want: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag = tt.tag
+			resumed, err := newImportState(path, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resumed.pending([]string{"a", "b"}); !slices.Equal(got, tt.want) {
+				t.Errorf("pending = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
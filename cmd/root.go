@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/blaxel-ai/mcp-hub/internal/profile"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +21,61 @@ var (
 	mcp        string
 	skipBuild  bool
 	tag        string
+	latest     bool
 	debug      bool
+
+	secretProvider   string
+	proxy            string
+	buildCache       bool
+	pull             bool
+	lintDockerfile   bool
+	registryInsecure bool
+	branch           string
+	commit           string
+	sign             bool
+	sbom             bool
+	signRequired     bool
+
+	maxLongDescription   int
+	sanitize             bool
+	integrationsPath     string
+	includeDisabled      bool
+	reportOut            string
+	checkEnv             bool
+	cloneConcurrency     int
+	buildConcurrency     int
+	catalogToRegistry    bool
+	platforms            []string
+	profileName          string
+	profilesPath         string
+	since                string
+	catalogFormat        string
+	vocabularyPath       string
+	memory               string
+	cpus                 float64
+	visibility           string
+	scanSecrets          bool
+	outputTemplate       string
+	locked               bool
+	lockPath             string
+	catalogStore         string
+	catalogStorePath     string
+	archive              bool
+	group                string
+	inlineIcons          bool
+	forcePush            bool
+	mirrorRemote         string
+	allowLicenses        []string
+	catalogOnly          bool
+	maxImageSize         string
+	skipSchemaValidation bool
+	catalogWatch         bool
+	includeDeprecated    bool
+	hostPort             int
+	resume               bool
+	statePath            string
+	referrers            bool
+	reproducible         bool
 )
 
 var rootCmd = &cobra.Command{
@@ -33,6 +93,92 @@ func Execute() {
 	}
 }
 
+// readHub reads the hub config and validates it, applying the default values
+func readHub() (*hub.Hub, error) {
+	path, err := hub.ResolvePath(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("fetch config: %w", err)
+	}
+	h := &hub.Hub{}
+	if err := h.Read(path); err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	if err := h.ValidateWithDefaultValues(); err != nil {
+		return nil, fmt.Errorf("validate config file: %w", err)
+	}
+	if err := h.ValidateLongDescription(maxLongDescription); err != nil {
+		return nil, fmt.Errorf("validate long descriptions: %w", err)
+	}
+	if err := h.ValidateIntegrations(hub.NewIntegrationSource(integrationsPath)); err != nil {
+		return nil, fmt.Errorf("validate integrations: %w", err)
+	}
+	if vocabularyPath != "" {
+		vocabulary, err := hub.ReadVocabulary(vocabularyPath)
+		if err != nil {
+			return nil, fmt.Errorf("read vocabulary: %w", err)
+		}
+		if err := h.ValidateVocabulary(vocabulary); err != nil {
+			return nil, fmt.Errorf("validate vocabulary: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// applyProfile applies the --profile settings to the flags not explicitly set,
+// it returns whether the tag is set by the flag or the profile
+func applyProfile(cmd *cobra.Command) (bool, error) {
+	explicitTag := cmd.Flags().Changed("tag")
+	if profileName == "" {
+		return explicitTag, nil
+	}
+	p, err := profile.Read(profilesPath, profileName)
+	if err != nil {
+		return false, err
+	}
+	if p.Registry != "" && !cmd.Flags().Changed("registry") {
+		registry = p.Registry
+	}
+	if p.Tag != "" && !explicitTag {
+		tag = p.Tag
+		explicitTag = true
+	}
+	if len(p.Platforms) > 0 && !cmd.Flags().Changed("platform") {
+		platforms = p.Platforms
+	}
+	if p.Push != nil && !cmd.Flags().Changed("push") {
+		push = *p.Push
+	}
+	return explicitTag, nil
+}
+
+// resolveTag returns the image tag: the --tag flag when explicitly set,
+// then the VERSION file of the config directory, then git describe of the hub repository.
+// Local runs fall back to latest, pushes only publish latest when --latest is set.
+func resolveTag(explicit bool, configDir string) (string, error) {
+	if explicit {
+		return tag, nil
+	}
+	if content, err := os.ReadFile(filepath.Join(configDir, "VERSION")); err == nil {
+		if version := strings.TrimSpace(string(content)); version != "" {
+			return version, nil
+		}
+	}
+	if output, err := exec.Command("git", "-C", configDir, "describe", "--tags").Output(); err == nil {
+		if version := strings.TrimSpace(string(output)); version != "" {
+			return version, nil
+		}
+	}
+	if push {
+		if latest {
+			// The latest tag is added by --latest
+			return "", nil
+		}
+		return "", errors.New("no --tag, VERSION file or git tag found, set --tag or --latest to push")
+	}
+	log.Printf("Warning: no --tag, VERSION file or git tag found, falling back to latest")
+	return "latest", nil
+}
+
 // handleError is a helper function for consistent error handling across commands
 func handleError(operation string, err error) {
 	if err != nil {
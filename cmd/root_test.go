@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveTag(t *testing.T) {
+	tagged, commits := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"brave.yaml": "displayName: Brave\n"}})
+	repo, err := git.PlainOpen(tagged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateTag("v1.2.0", plumbing.NewHash(commits["main"]), nil); err != nil {
+		t.Fatal(err)
+	}
+	versioned, _ := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"VERSION": " 2.0.0\n"}})
+	untagged, _ := gitFixture(t, fixtureBranch{name: "main", files: map[string]string{"brave.yaml": "displayName: Brave\n"}})
+
+	tests := []struct {
+		name     string
+		explicit bool
+		dir      string
+		push     bool
+		latest   bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "explicit tag", explicit: true, dir: versioned, want: "v1"},
+		{name: "VERSION file", dir: versioned, want: "2.0.0"},
+		{name: "git describe", dir: tagged, want: "v1.2.0"},
+		{name: "fallback to latest", dir: untagged, want: "latest"},
+		{name: "outside of a git repository", dir: t.TempDir(), want: "latest"},
+		{name: "push requires a tag", dir: untagged, push: true, wantErr: true},
+		{name: "push with --latest only", dir: untagged, push: true, latest: true, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(t, &tag, "v1")
+			setFlag(t, &push, tt.push)
+			setFlag(t, &latest, tt.latest)
+			got, err := resolveTag(tt.explicit, tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("tag = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	writeFiles(t, filepath.Dir(path), map[string]string{
+		"profiles.yaml": "prod:\n  registry: ghcr.io/hub\n  tag: v2\n  platforms: [linux/amd64, linux/arm64]\n  push: true\n",
+	})
+	tests := []struct {
+		name         string
+		args         []string
+		wantRegistry string
+		wantTag      string
+		wantPush     bool
+	}{
+		{name: "profile values", wantRegistry: "ghcr.io/hub", wantTag: "v2", wantPush: true},
+		{name: "explicit flags override", args: []string{"--registry", "localhost:5000/hub", "--tag", "v3", "--push=false"}, wantRegistry: "localhost:5000/hub", wantTag: "v3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(t, &registry, "")
+			setFlag(t, &tag, "")
+			setFlag(t, &platforms, nil)
+			setFlag(t, &push, false)
+			setFlag(t, &profileName, "prod")
+			setFlag(t, &profilesPath, path)
+			cmd := &cobra.Command{}
+			cmd.Flags().StringVarP(&registry, "registry", "r", "ghcr.io/blaxel-ai/hub", "")
+			cmd.Flags().StringVarP(&tag, "tag", "t", "", "")
+			cmd.Flags().StringSliceVar(&platforms, "platform", nil, "")
+			cmd.Flags().BoolVarP(&push, "push", "p", false, "")
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			explicitTag, err := applyProfile(cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !explicitTag || registry != tt.wantRegistry || tag != tt.wantTag || push != tt.wantPush {
+				t.Errorf("got registry %s, tag %s (explicit %t), push %t", registry, tag, explicitTag, push)
+			}
+			if !slices.Equal(platforms, []string{"linux/amd64", "linux/arm64"}) {
+				t.Errorf("platforms = %v, want the profile platforms", platforms)
+			}
+		})
+	}
+
+	setFlag(t, &profileName, "staging")
+	setFlag(t, &profilesPath, path)
+	if _, err := applyProfile(&cobra.Command{}); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
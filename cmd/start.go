@@ -1,14 +1,25 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
+	"net"
 	"os"
 	"os/exec"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/blaxel-ai/mcp-hub/internal/docker"
 	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/blaxel-ai/mcp-hub/internal/secrets"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
@@ -21,13 +32,28 @@ var startCmd = &cobra.Command{
 }
 
 func init() {
-	startCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files")
+	startCmd.Flags().StringVarP(&configPath, "config", "c", "", "The path to the config files, or the http(s) URL of a tarball of them")
 	startCmd.Flags().BoolVarP(&push, "push", "p", false, "Push the images to the registry")
 	startCmd.Flags().StringVarP(&registry, "registry", "r", "ghcr.io/blaxel-ai/hub", "The registry to push the images to")
+	startCmd.Flags().BoolVar(&registryInsecure, "registry-insecure", false, "Allow pushing to a registry over plain HTTP, it must be an insecure registry of the docker daemon")
 	startCmd.Flags().StringVarP(&mcp, "mcp", "m", "", "The MCP to import, if not provided")
-	startCmd.Flags().BoolVarP(&skipBuild, "skip-build", "s", false, "Skip building the image")
-	startCmd.Flags().StringVarP(&tag, "tag", "t", "latest", "The tag to use for the image")
+	startCmd.Flags().StringVar(&branch, "branch", "", "Override the branch of the MCP repository")
+	startCmd.Flags().StringVar(&commit, "commit", "", "Override the commit of the MCP repository, takes precedence over the branch head")
+	startCmd.Flags().BoolVarP(&skipBuild, "skip-build", "s", false, "Run the image built before from the catalog labeled on it, without cloning nor building")
+	startCmd.Flags().BoolVar(&pull, "pull", false, "Pull the base images before building to fail fast on bad references")
+	startCmd.Flags().BoolVar(&lintDockerfile, "lint-dockerfile", false, "Check the COPY and ADD sources and stages of the injected Dockerfile exist before building")
+	startCmd.Flags().StringVarP(&tag, "tag", "t", "", "The tag to use for the image, defaults to the VERSION file of the config, git describe or latest")
+	startCmd.Flags().StringSliceVar(&platforms, "platform", nil, "The platforms to build the image for, defaults to the platform of the docker daemon")
+	startCmd.Flags().StringVar(&profileName, "profile", "", "The profile of profiles.yaml setting the registry, tag, platforms and push, explicit flags take precedence")
+	startCmd.Flags().StringVar(&profilesPath, "profiles", "profiles.yaml", "The path to the profiles file")
 	startCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug mode, will not save the catalog")
+	startCmd.Flags().StringVar(&proxy, "proxy", "", "The proxy to use for clone and build, defaults to the HTTP_PROXY and HTTPS_PROXY environment variables")
+	startCmd.Flags().IntVar(&maxLongDescription, "max-long-description", 2000, "The maximum length of a long description, 0 to disable the check")
+	startCmd.Flags().StringVar(&integrationsPath, "integrations", "", "The file listing the known integrations, one per line, defaults to the embedded list")
+	startCmd.Flags().StringVar(&memory, "memory", "", "Override the memory limit of the container, e.g. 512m")
+	startCmd.Flags().Float64Var(&cpus, "cpus", 0, "Override the number of CPUs the container can use")
+	startCmd.Flags().StringVar(&secretProvider, "secret-provider", secrets.ProviderEnv, "The provider to read the MCP secrets from (env, vault)")
+	startCmd.Flags().IntVar(&hostPort, "port", 1400, "The host port the gateway of the MCP listens on, 0 to pick a free port")
 	rootCmd.AddCommand(startCmd)
 }
 
@@ -44,71 +70,242 @@ func runStart(cmd *cobra.Command, args []string) {
 		log.Printf("MCP is required")
 		os.Exit(1)
 	}
+	if hostPort < 0 || hostPort > 65535 {
+		log.Printf("Invalid port %d", hostPort)
+		os.Exit(1)
+	}
+
+	repository, artifact, envValues := loadMCP(cmd)
+	if cmd.Flags().Changed("memory") {
+		repository.Run.Memory = memory
+	}
+	if cmd.Flags().Changed("cpus") {
+		repository.Run.CPUs = cpus
+	}
+	handleError("validate run options", repository.Run.Validate())
+	port := hostPort
+	if port == 0 {
+		var err error
+		port, err = freePort()
+		handleError("find a free port", err)
+	}
+	log.Printf("Starting MCP %s on port %d", mcp, port)
+	err := dockerRun(artifact, envValues, repository.Run, port)
+	if err != nil {
+		log.Printf("Failed to run docker command: %v", err)
+		os.Exit(1)
+	}
+}
 
+// loadMCP builds the image of --mcp, or reads the catalog artifact of its prebuilt image with --skip-build, and
+// returns its repository, catalog artifact and the values of its environment variables
+func loadMCP(cmd *cobra.Command) (*hub.Repository, catalog.Artifact, map[string]string) {
 	// We set debug to true to avoid saving the catalog in control plane
 	debug = true
 
-	hub := hub.Hub{}
-	handleError("read config file", hub.Read(configPath))
-	handleError("validate config file", hub.ValidateWithDefaultValues())
+	explicitTag, err := applyProfile(cmd)
+	handleError("apply profile", err)
+
+	if push && registryInsecure {
+		handleError("check insecure registry", docker.CheckInsecureRegistry(context.Background(), registry))
+	}
+
+	provider, err := secrets.NewSecretProvider(secretProvider)
+	handleError("create secret provider", err)
+
+	hub, err := readHub()
+	handleError("load config", err)
+	tag, err = resolveTag(explicitTag, hub.Path)
+	handleError("resolve tag", err)
 
 	repository := hub.Repositories[mcp]
 	if repository == nil {
 		log.Printf("Repository %s not found", mcp)
 		os.Exit(1)
 	}
-	c, err := processRepository(mcp, repository)
-	if err != nil {
-		log.Printf("Failed to process repository %s: %v", mcp, err)
-		os.Exit(1)
-	}
-	artifact := c.Artifacts[0]
-	envKeys := []string{}
-	for key := range artifact.Entrypoint.Env {
-		envKeys = append(envKeys, key)
-		err := checkEnvironmentVariable(artifact, key, artifact.Entrypoint.Env[key])
+	handleError("override repository ref", repository.OverrideRef(branch, commit))
+	var artifact catalog.Artifact
+	if skipBuild {
+		if artifact, err = prebuiltArtifact(mcp); err != nil {
+			log.Print(err)
+			os.Exit(1)
+		}
+	} else {
+		c, err := processRepository(mcp, repository, &importResult{Name: mcp})
 		if err != nil {
-			log.Printf(err.Error())
+			log.Printf("Failed to process repository %s: %v", mcp, err)
 			os.Exit(1)
 		}
+		artifact = c.Artifacts[0]
 	}
-	log.Printf("Starting MCP %s", mcp)
-	err = dockerRun(artifact, envKeys)
+	envValues, err := resolveEnv(mcp, artifact, provider)
 	if err != nil {
-		log.Printf("Failed to run docker command: %v", err)
+		log.Print(err)
 		os.Exit(1)
 	}
+	return repository, artifact, envValues
 }
 
-func dockerRun(artifact catalog.Artifact, envKeys []string) error {
-	name := fmt.Sprintf("mcp-hub-%s", mcp)
-	exec.Command("docker", "rm", "-f", name).Run()
-	dockerRunCmd := []string{"run", "--rm", "-i", "-p", "1400:80", "--name", name}
-	for _, key := range envKeys {
-		dockerRunCmd = append(dockerRunCmd, "-e", fmt.Sprintf("%s=%s", key, os.Getenv(key)))
+// prebuiltArtifact returns the catalog artifact labeled on the local image of the MCP name, without cloning nor
+// building its repository
+func prebuiltArtifact(name string) (catalog.Artifact, error) {
+	tags, err := imageTags(tag, latest)
+	if err != nil {
+		return catalog.Artifact{}, err
 	}
-	dockerRunCmd = append(dockerRunCmd, artifact.Image)
+	imageName := fmt.Sprintf("%s/%s:%s", strings.ToLower(registry), strings.ToLower(name), tags[0])
+	if !docker.ImageExists(context.Background(), imageName) {
+		return catalog.Artifact{}, fmt.Errorf("Image %s not found locally, build it first or run without --skip-build", imageName)
+	}
+	label, err := docker.ImageLabel(context.Background(), imageName, docker.CatalogLabel)
+	if err != nil {
+		return catalog.Artifact{}, err
+	}
+	if label == "" {
+		return catalog.Artifact{}, fmt.Errorf("Image %s has no %s label, rebuild it or run without --skip-build", imageName, docker.CatalogLabel)
+	}
+	var artifact catalog.Artifact
+	if err := json.Unmarshal([]byte(label), &artifact); err != nil {
+		return catalog.Artifact{}, fmt.Errorf("Invalid %s label of image %s: %w", docker.CatalogLabel, imageName, err)
+	}
+	artifact.Image = imageName
+	return artifact, nil
+}
 
-	dockerCmd := artifact.Entrypoint.Command
-	for _, arg := range artifact.Entrypoint.Args {
-		dockerCmd += " " + arg
+// resolveEnv returns the values of the environment variables of the MCP name read from the secret provider,
+// it fails when a required one is empty
+func resolveEnv(name string, artifact catalog.Artifact, provider secrets.SecretProvider) (map[string]string, error) {
+	envValues := map[string]string{}
+	for key, val := range artifact.Entrypoint.Env {
+		value, err := provider.Get(key)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read secret %s: %w", key, err)
+		}
+		if err := checkEnvironmentVariable(name, artifact, key, val, value); err != nil {
+			return nil, err
+		}
+		envValues[key] = value
 	}
-	dockerRunCmd = append(dockerRunCmd, dockerCmd)
+	return envValues, nil
+}
+
+func dockerRun(artifact catalog.Artifact, envValues map[string]string, run hub.Run, port int) error {
+	name := containerName("mcp-hub")
+	exec.Command("docker", "rm", "-f", name).Run()
+	dockerRunCmd := dockerRunArgs(name, artifact, envValues, run, port)
 
 	cmd := exec.Command("docker", dockerRunCmd...)
 	// Connect command's stdout and stderr to our process stdout and stderr
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
+	// Forward the signals to docker, which proxies them to the container, instead of exiting before it stops
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
 	// Run the command and wait for it to finish
-	err := cmd.Run()
+	err := cmd.Start()
+	if err == nil {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			for {
+				select {
+				case sig := <-signals:
+					cmd.Process.Signal(sig)
+				case <-done:
+					return
+				}
+			}
+		}()
+		err = cmd.Wait()
+	}
 	if err != nil {
-		return fmt.Errorf("Failed to run docker command \"docker %s\": %v", strings.Join(dockerRunCmd, " "), err)
+		redacted := docker.RedactArgs(dockerRunCmd, secretEnvKeys(artifact))
+		return fmt.Errorf("Failed to run docker command \"docker %s\": %v", strings.Join(redacted, " "), err)
 	}
 	return nil
 }
 
-func checkEnvironmentVariable(artifact catalog.Artifact, key string, val string) error {
+// invalidNameChars are the characters not allowed in the name of a container
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// containerName returns the name of the container of --mcp and --tag, so runs of different MCPs or tags don't
+// remove each other's container
+func containerName(prefix string) string {
+	name := strings.Join([]string{prefix, mcp, tag}, "-")
+	return invalidNameChars.ReplaceAllString(strings.TrimSuffix(name, "-"), "-")
+}
+
+// freePort returns a host port free at the time of the call
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// dockerRunArgs returns the arguments of the docker run command starting the MCP with its gateway on the host port
+func dockerRunArgs(name string, artifact catalog.Artifact, envValues map[string]string, run hub.Run, port int) []string {
+	dockerRunCmd := []string{"run", "--rm", "-i", "-p", fmt.Sprintf("%d:80", port), "--name", name}
+	dockerRunCmd = append(dockerRunCmd, runOptionArgs(envValues, run)...)
+	dockerRunCmd = append(dockerRunCmd, artifact.Image)
+	return append(dockerRunCmd, entrypointCommand(artifact))
+}
+
+// secretEnvKeys returns the environment variables of the MCP set from one of its secrets
+func secretEnvKeys(artifact catalog.Artifact) []string {
+	var keys []string
+	for key, val := range artifact.Entrypoint.Env {
+		name := strings.Trim(val, "$")
+		if _, ok := artifact.Form.Secrets[name]; ok || slices.Contains(artifact.HiddenSecrets, name) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// runOptionArgs returns the docker run arguments of the run options and environment of the MCP
+func runOptionArgs(envValues map[string]string, run hub.Run) []string {
+	var dockerRunCmd []string
+	if run.InitEnabled() {
+		dockerRunCmd = append(dockerRunCmd, "--init")
+	}
+	if run.User != "" {
+		dockerRunCmd = append(dockerRunCmd, "--user", run.User)
+	}
+	if run.Memory != "" {
+		dockerRunCmd = append(dockerRunCmd, "--memory", run.Memory)
+	}
+	if run.CPUs > 0 {
+		dockerRunCmd = append(dockerRunCmd, "--cpus", strconv.FormatFloat(run.CPUs, 'f', -1, 64))
+	}
+	if run.ReadOnlyRootfs {
+		dockerRunCmd = append(dockerRunCmd, "--read-only")
+	}
+	for _, path := range run.Tmpfs {
+		dockerRunCmd = append(dockerRunCmd, "--tmpfs", path)
+	}
+	for _, key := range slices.Sorted(maps.Keys(envValues)) {
+		dockerRunCmd = append(dockerRunCmd, "-e", fmt.Sprintf("%s=%s", key, envValues[key]))
+	}
+	return dockerRunCmd
+}
+
+// entrypointCommand returns the command line of the MCP server, run by the gateway of the image
+func entrypointCommand(artifact catalog.Artifact) string {
+	dockerCmd := artifact.Entrypoint.Command
+	for _, arg := range artifact.Entrypoint.Args {
+		dockerCmd += " " + arg
+	}
+	return dockerCmd
+}
+
+// checkEnvironmentVariable returns an error when the value of a required environment variable of the MCP name is empty
+func checkEnvironmentVariable(name string, artifact catalog.Artifact, key string, val string, value string) error {
 	trimedVal := strings.Trim(val, "$")
 	required := false
 
@@ -120,8 +317,8 @@ func checkEnvironmentVariable(artifact catalog.Artifact, key string, val string)
 		required = artifact.Form.Secrets[trimedVal].Required
 	}
 
-	if required && os.Getenv(key) == "" {
-		return fmt.Errorf("Environment variable %s is not set and is required for the MCP %s", key, mcp)
+	if required && value == "" {
+		return fmt.Errorf("Environment variable %s is not set and is required for the MCP %s", key, name)
 	}
 	return nil
 }
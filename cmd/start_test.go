@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/catalog"
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/blaxel-ai/mcp-hub/internal/smithery"
+)
+
+// fakeProvider is a secret provider reading the secrets of a map, or failing with err
+type fakeProvider struct {
+	values map[string]string
+	err    error
+}
+
+func (p *fakeProvider) Get(key string) (string, error) {
+	return p.values[key], p.err
+}
+
+// testArtifact returns the artifact of a MCP whose API_KEY is set from a required secret and BASE_URL from an
+// optional config
+func testArtifact() catalog.Artifact {
+	return catalog.Artifact{
+		Name:  "brave",
+		Image: "ghcr.io/hub/brave:v1",
+		Form: catalog.Form{
+			Config:  map[string]catalog.Field{"baseUrl": {}},
+			Secrets: map[string]catalog.Field{"apiKey": {Required: true}},
+		},
+		Entrypoint: catalog.Entrypoint{
+			Command: "node",
+			Args:    []string{"dist/index.js"},
+			Env:     map[string]string{"API_KEY": "$apiKey", "BASE_URL": "$baseUrl"},
+		},
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider *fakeProvider
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "secrets reach the run args",
+			provider: &fakeProvider{values: map[string]string{"API_KEY": "secret", "BASE_URL": "https://api.example.com"}},
+			want:     []string{"-e", "API_KEY=secret", "-e", "BASE_URL=https://api.example.com"},
+		},
+		{
+			name:     "optional value left empty",
+			provider: &fakeProvider{values: map[string]string{"API_KEY": "secret"}},
+			want:     []string{"-e", "API_KEY=secret", "-e", "BASE_URL="},
+		},
+		{
+			name:     "required secret missing",
+			provider: &fakeProvider{values: map[string]string{"BASE_URL": "https://api.example.com"}},
+			wantErr:  "API_KEY is not set",
+		},
+		{
+			name:     "provider failure",
+			provider: &fakeProvider{err: errors.New("vault sealed")},
+			wantErr:  "vault sealed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact := testArtifact()
+			envValues, err := resolveEnv("brave", artifact, tt.provider)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			args := dockerRunArgs("mcp-hub-brave", artifact, envValues, hub.Run{}, 1400)
+			start := slices.Index(args, "-e")
+			if start < 0 || !slices.Equal(args[start:start+len(tt.want)], tt.want) {
+				t.Errorf("args = %v, want %v", args, tt.want)
+			}
+		})
+	}
+}
+
+// remoteBraveConfig is the config of a MCP whose repository can't be cloned, with an inline smithery config
+const remoteBraveConfig = `repository: https://git.invalid/hub/brave.git
+branch: main
+dockerfile: Dockerfile
+displayName: Brave Search
+license: MIT
+url: https://brave.com/search/api
+icon: https://brave.com/logo.svg
+description: Search the web using Brave's search engine.
+longDescription: Search the web using Brave's search engine.
+integration: brave-search
+secrets:
+  - braveApiKey
+smithery:
+  startCommand:
+    type: stdio
+    configSchema:
+      type: object
+      required:
+        - braveApiKey
+      properties:
+        braveApiKey:
+          type: string
+          description: The API key of Brave search.
+    commandFunction: |-
+      config=>({command:'node',args:['dist/index.js'],env:{BRAVE_API_KEY:config.braveApiKey}})
+`
+
+func TestStartSkipBuild(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"hub/brave.yaml": remoteBraveConfig})
+	artifact := testArtifact()
+	artifact.Image = "registry.test/brave:old"
+	artifact.Entrypoint.Env = map[string]string{"BRAVE_API_KEY": "$apiKey"}
+	label, err := json.Marshal(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{"label.json": string(label)})
+
+	tests := []struct {
+		name   string
+		docker string
+		code   int
+		stderr string
+	}{
+		{
+			name:   "prebuilt image",
+			docker: `if [ "$1 $2 $3" = "image inspect --format" ]; then cat ` + filepath.Join(dir, "label.json") + `; fi`,
+		},
+		{
+			name:   "missing image",
+			docker: `if [ "$1 $2" = "image inspect" ]; then exit 1; fi`,
+			code:   1,
+			stderr: "Image registry.test/brave:v1 not found locally",
+		},
+		{
+			name:   "image without catalog label",
+			docker: `if [ "$1 $2 $3" = "image inspect --format" ]; then echo "<no value>"; fi`,
+			code:   1,
+			stderr: "Image registry.test/brave:v1 has no hub.catalog label",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerLog := fakeTool(t, "docker", tt.docker)
+			result := runCLI(t, dir, []string{"BRAVE_API_KEY=secret"},
+				"start", "-c", "hub", "-m", "brave", "--skip-build", "--tag", "v1", "--registry", "registry.test", "--port", "1500")
+			if result.code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr:\n%s", result.code, tt.code, result.stderr)
+			}
+			if !strings.Contains(result.stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want %q", result.stderr, tt.stderr)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tmpDir)); !os.IsNotExist(err) {
+				t.Error("the repository was cloned")
+			}
+			calls := toolCalls(t, dockerLog)
+			for _, call := range calls {
+				if strings.HasPrefix(call, "build") || strings.HasPrefix(call, "buildx") {
+					t.Errorf("image built: docker %s", call)
+				}
+			}
+			if tt.code != 0 {
+				return
+			}
+			run := calls[len(calls)-1]
+			if !strings.HasPrefix(run, "run ") || !strings.HasSuffix(run, "-e BRAVE_API_KEY=secret registry.test/brave:v1 node dist/index.js") {
+				t.Errorf("last docker call %q, want the run of the prebuilt image", run)
+			}
+		})
+	}
+}
+
+func TestImageArtifact(t *testing.T) {
+	repository := &hub.Repository{DisplayName: "Brave Search", Secrets: []string{"braveApiKey"}}
+	cfg := &smithery.SmitheryConfig{}
+	cfg.StartCommand.ConfigSchema.Properties = map[string]smithery.Property{"braveApiKey": {Type: "string"}}
+	cfg.ParsedCommand = &smithery.Command{Command: "node", Env: map[string]string{"BRAVE_API_KEY": "$braveApiKey"}}
+
+	label, err := imageArtifact("brave", repository, "registry.test/brave:v1", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var artifact catalog.Artifact
+	if err := json.Unmarshal([]byte(label), &artifact); err != nil {
+		t.Fatal(err)
+	}
+	if artifact.Entrypoint.Command != "node" || artifact.Form.Secrets["braveApiKey"].Type != "string" {
+		t.Errorf("got artifact %+v", artifact)
+	}
+}
+
+func TestRunOptionArgs(t *testing.T) {
+	envValues := map[string]string{"API_KEY": "secret"}
+	tests := []struct {
+		name string
+		run  hub.Run
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{"--init", "-e", "API_KEY=secret"},
+		},
+		{
+			name: "init disabled",
+			run:  hub.Run{Init: ptr(false)},
+			want: []string{"-e", "API_KEY=secret"},
+		},
+		{
+			name: "init enabled",
+			run:  hub.Run{Init: ptr(true)},
+			want: []string{"--init", "-e", "API_KEY=secret"},
+		},
+		{
+			name: "non-root user with a read-only rootfs",
+			run:  hub.Run{User: "1000:1000", ReadOnlyRootfs: true, Tmpfs: []string{"/tmp", "/home/node/.npm:size=64m"}},
+			want: []string{"--init", "--user", "1000:1000", "--read-only", "--tmpfs", "/tmp", "--tmpfs", "/home/node/.npm:size=64m", "-e", "API_KEY=secret"},
+		},
+		{
+			name: "resource limits",
+			run:  hub.Run{Memory: "512m", CPUs: 1.5},
+			want: []string{"--init", "--memory", "512m", "--cpus", "1.5", "-e", "API_KEY=secret"},
+		},
+		{
+			name: "every option",
+			run:  hub.Run{Init: ptr(false), User: "node", Memory: "1g", CPUs: 2, ReadOnlyRootfs: true, Tmpfs: []string{"/tmp"}},
+			want: []string{"--user", "node", "--memory", "1g", "--cpus", "2", "--read-only", "--tmpfs", "/tmp", "-e", "API_KEY=secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runOptionArgs(envValues, tt.run); !slices.Equal(got, tt.want) {
+				t.Errorf("got  %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestContainerName(t *testing.T) {
+	tests := []struct {
+		mcp  string
+		tag  string
+		want string
+	}{
+		{mcp: "brave", tag: "v1", want: "mcp-hub-brave-v1"},
+		{mcp: "brave", tag: "v2", want: "mcp-hub-brave-v2"},
+		{mcp: "exa", tag: "v1", want: "mcp-hub-exa-v1"},
+		{mcp: "brave", want: "mcp-hub-brave"},
+		{mcp: "brave", tag: "feat/sse+1", want: "mcp-hub-brave-feat-sse-1"},
+	}
+	for _, tt := range tests {
+		setFlag(t, &mcp, tt.mcp)
+		setFlag(t, &tag, tt.tag)
+		if got := containerName("mcp-hub"); got != tt.want {
+			t.Errorf("containerName(%s, %s) = %s, want %s", tt.mcp, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestStartConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"hub/brave.yaml": remoteBraveConfig, "hub/exa.yaml": remoteBraveConfig})
+	artifact := testArtifact()
+	artifact.Entrypoint.Env = map[string]string{"BRAVE_API_KEY": "$apiKey"}
+	label, err := json.Marshal(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{"label.json": string(label)})
+	// The runs overlap so a shared name or port would collide
+	dockerLog := fakeTool(t, "docker", `case "$1 $2 $3" in
+"image inspect --format") cat `+filepath.Join(dir, "label.json")+` ;;
+run*) sleep 0.5 ;;
+esac`)
+
+	results := make(chan cliResult, 2)
+	for _, name := range []string{"brave", "exa"} {
+		go func() {
+			results <- runCLI(t, dir, []string{"BRAVE_API_KEY=secret"},
+				"start", "-c", "hub", "-m", name, "--skip-build", "--tag", "v1", "--registry", "registry.test", "--port", "0")
+		}()
+	}
+	for range 2 {
+		if result := <-results; result.code != 0 {
+			t.Fatalf("exit code %d, stderr:\n%s", result.code, result.stderr)
+		}
+	}
+
+	ports := make(map[string]string)
+	for _, call := range toolCalls(t, dockerLog) {
+		args := strings.Fields(call)
+		if args[0] != "run" {
+			continue
+		}
+		port := args[slices.Index(args, "-p")+1]
+		name := args[slices.Index(args, "--name")+1]
+		if port == "0:80" {
+			t.Errorf("%s published on port 0, want a free port", name)
+		}
+		ports[name] = port
+	}
+	if len(ports) != 2 || ports["mcp-hub-brave-v1"] == "" || ports["mcp-hub-exa-v1"] == "" {
+		t.Fatalf("containers %v, want one per MCP", ports)
+	}
+	if ports["mcp-hub-brave-v1"] == ports["mcp-hub-exa-v1"] {
+		t.Errorf("both MCPs published on %s", ports["mcp-hub-brave-v1"])
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is the quiet period after a change before the catalog is generated again,
+// editors write a file in several events
+const watchDebounce = 300 * time.Millisecond
+
+// watchCatalog prints the catalog, then prints it again after every change of the config directory or of the
+// local path of the MCP, the errors are printed and the watch goes on until ctx is done
+func watchCatalog(ctx context.Context, printCatalog func() error) error {
+	if info, err := os.Stat(configPath); err != nil || !info.IsDir() {
+		return fmt.Errorf("--watch requires a local config directory, %s is not one", configPath)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	paths := []string{configPath}
+	if h, err := readHub(); err == nil && h.Repositories[mcp] != nil && h.Repositories[mcp].Path != "" {
+		paths = append(paths, h.Repositories[mcp].Path)
+	}
+	for _, path := range paths {
+		if err := watchTree(watcher, path); err != nil {
+			return err
+		}
+	}
+
+	render := func() {
+		if err := printCatalog(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate catalog for %s: %v\n", mcp, err)
+		}
+		log.Printf("Watching %v for changes", paths)
+	}
+	render()
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// New directories of the source are watched too
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					watchTree(watcher, event.Name)
+				}
+			}
+			debounce.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Warning: watch error: %v", err)
+		case <-debounce.C:
+			render()
+		}
+	}
+}
+
+// watchTree watches a directory and its subdirectories, fsnotify only watches the entries of a directory
+func watchTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		switch entry.Name() {
+		case ".git", "node_modules":
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchCatalog(t *testing.T) {
+	dir := testConfig(t)
+	setFlag(t, &configPath, filepath.Join(dir, "hub"))
+	setFlag(t, &mcp, "brave")
+
+	// Every render reads the config again and reports the display name, or the parse error
+	renders := make(chan string, 10)
+	printCatalog := func() error {
+		h, err := readHub()
+		if err != nil {
+			renders <- "error"
+			return err
+		}
+		renders <- h.Repositories["brave"].DisplayName
+		return nil
+	}
+	nextRender := func(want string) {
+		t.Helper()
+		select {
+		case got := <-renders:
+			if got != want {
+				t.Fatalf("rendered %q, want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no render after the change, want %q", want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watchCatalog(ctx, printCatalog) }()
+	nextRender("Brave Search")
+
+	config := fmt.Sprintf(braveConfig, filepath.Join(dir, "src"))
+	writeFiles(t, dir, map[string]string{"hub/brave.yaml": strings.Replace(config, "Brave Search", "Brave", 1)})
+	nextRender("Brave")
+
+	// A parse error is printed and the watch goes on
+	writeFiles(t, dir, map[string]string{"hub/brave.yaml": "displayName: [Brave\n"})
+	nextRender("error")
+	writeFiles(t, dir, map[string]string{"hub/brave.yaml": config})
+	nextRender("Brave Search")
+
+	// The local path of the MCP is watched, new directories included
+	writeFiles(t, dir, map[string]string{"src/lib/index.js": "console.log('brave')"})
+	nextRender("Brave Search")
+	time.Sleep(2 * watchDebounce)
+	writeFiles(t, dir, map[string]string{"src/lib/index.js": "console.log('brave search')"})
+	nextRender("Brave Search")
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch did not stop with its context")
+	}
+	if len(renders) != 0 {
+		t.Errorf("%d more renders, want one per change", len(renders))
+	}
+}
+
+func TestWatchCatalogRemoteConfig(t *testing.T) {
+	setFlag(t, &configPath, "https://example.com/hub.yaml")
+	err := watchCatalog(context.Background(), func() error { return nil })
+	if err == nil || err.Error() != "--watch requires a local config directory, https://example.com/hub.yaml is not one" {
+		t.Errorf("err = %v, want the remote config refused", err)
+	}
+}
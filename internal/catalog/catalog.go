@@ -1,12 +1,10 @@
 package catalog
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"os"
 	"slices"
+	"time"
 
 	"github.com/blaxel-ai/mcp-hub/internal/hub"
 	"github.com/blaxel-ai/mcp-hub/internal/smithery"
@@ -17,20 +15,37 @@ const (
 )
 
 type Artifact struct {
-	Name            string     `json:"name"`
-	Image           string     `json:"image"`
-	Enterprise      bool       `json:"enterprise"`
-	ComingSoon      bool       `json:"coming_soon"`
-	DisplayName     string     `json:"displayName"`
-	Categories      []string   `json:"categories"`
-	Integration     string     `json:"integration"`
-	Description     string     `json:"description"`
-	LongDescription string     `json:"longDescription"`
-	Icon            string     `json:"icon"`
-	URL             string     `json:"url"`
-	Form            Form       `json:"form"`
-	HiddenSecrets   []string   `json:"hiddenSecrets"`
-	Entrypoint      Entrypoint `json:"entrypoint"`
+	Name              string            `json:"name"`
+	Image             string            `json:"image"`
+	Enterprise        bool              `json:"enterprise"`
+	ComingSoon        bool              `json:"coming_soon"`
+	DisplayName       string            `json:"displayName"`
+	Categories        []string          `json:"categories"`
+	Integration       string            `json:"integration"`
+	Description       string            `json:"description"`
+	LongDescription   string            `json:"longDescription"`
+	Icon              string            `json:"icon"`
+	IconType          string            `json:"iconType,omitempty"`
+	URL               string            `json:"url"`
+	License           string            `json:"license,omitempty"`
+	Deprecated        bool              `json:"deprecated,omitempty"`
+	ReplacedBy        string            `json:"replacedBy,omitempty"`
+	DeprecationNotice string            `json:"deprecationNotice,omitempty"`
+	Form              Form              `json:"form"`
+	HiddenSecrets     []string          `json:"hiddenSecrets"`
+	Entrypoint        Entrypoint        `json:"entrypoint"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	Source            *Source           `json:"source,omitempty"`
+}
+
+// Source traces an artifact back to the source its image was built from
+type Source struct {
+	Repository string     `json:"repository,omitempty"`
+	Branch     string     `json:"branch,omitempty"`
+	Commit     string     `json:"commit,omitempty"`
+	ClonedAt   *time.Time `json:"clonedAt,omitempty"`
+	Path       string     `json:"path,omitempty"`
+	Note       string     `json:"note,omitempty"`
 }
 
 type Form struct {
@@ -40,6 +55,7 @@ type Form struct {
 }
 
 type Field struct {
+	Type        string `json:"type,omitempty"`
 	Description string `json:"description"`
 	Label       string `json:"label"`
 	Required    bool   `json:"required"`
@@ -60,48 +76,33 @@ type Entrypoint struct {
 
 type Catalog struct {
 	Artifacts []Artifact
+	// SkipValidation saves the artifacts without validating them against the catalog schema
+	SkipValidation bool
 }
 
 func (c *Catalog) AddArtifact(artifact Artifact) {
 	c.Artifacts = append(c.Artifacts, artifact)
 }
 
-func (c *Catalog) SaveArtifact(artifact Artifact) error {
+func (c *Catalog) SaveArtifact(store CatalogStore, artifact Artifact) error {
 	jsonData, err := json.MarshalIndent(artifact, "", "  ")
 	if err != nil {
 		return err
 	}
-
-	apiURL := os.Getenv("BL_API_URL")
-	username := os.Getenv("BL_ADMIN_USERNAME")
-	password := os.Getenv("BL_ADMIN_PASSWORD")
-
-	url := fmt.Sprintf("%s/admin/store/mcp/%s", apiURL, artifact.Name)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
-	}
-
-	req.SetBasicAuth(username, password)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	if !c.SkipValidation {
+		if err := ValidateArtifact(jsonData); err != nil {
+			return fmt.Errorf("invalid artifact: %w", err)
+		}
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("failed to save artifact: HTTP %d", resp.StatusCode)
+	if err := store.Put(artifact.Name, jsonData); err != nil {
+		return fmt.Errorf("failed to save artifact: %w", err)
 	}
-
 	return nil
 }
 
-func (c *Catalog) Save() error {
+func (c *Catalog) Save(store CatalogStore) error {
 	for _, artifact := range c.Artifacts {
-		err := c.SaveArtifact(artifact)
+		err := c.SaveArtifact(store, artifact)
 		if err != nil {
 			fmt.Printf("error saving artifact %s: %s\n", artifact.Name, err)
 			return err
@@ -111,20 +112,36 @@ func (c *Catalog) Save() error {
 	return nil
 }
 
+// deprecationNotice returns the notice of a deprecated repository pointing to its replacement, empty otherwise
+func deprecationNotice(repository *hub.Repository) string {
+	switch {
+	case !repository.Deprecated:
+		return ""
+	case repository.ReplacedBy != "":
+		return fmt.Sprintf("%s is deprecated, use %s instead", repository.DisplayName, repository.ReplacedBy)
+	default:
+		return fmt.Sprintf("%s is deprecated", repository.DisplayName)
+	}
+}
+
 func (c *Catalog) Load(name string, hub *hub.Repository, imageName string, smithery *smithery.SmitheryConfig) error {
 	if hub.Disabled {
 		c.AddArtifact(Artifact{
-			Name:            name,
-			Image:           imageName,
-			DisplayName:     hub.DisplayName,
-			Description:     hub.Description,
-			LongDescription: hub.LongDescription,
-			Icon:            hub.Icon,
-			Categories:      hub.Categories,
-			URL:             hub.URL,
-			Enterprise:      hub.Enterprise,
-			ComingSoon:      hub.ComingSoon,
-			Integration:     hub.Integration,
+			Name:              name,
+			Image:             imageName,
+			DisplayName:       hub.DisplayName,
+			Description:       hub.Description,
+			LongDescription:   hub.LongDescription,
+			Icon:              hub.Icon,
+			Categories:        hub.Categories,
+			URL:               hub.URL,
+			License:           hub.License,
+			Deprecated:        hub.Deprecated,
+			ReplacedBy:        hub.ReplacedBy,
+			DeprecationNotice: deprecationNotice(hub),
+			Enterprise:        hub.Enterprise,
+			ComingSoon:        hub.ComingSoon,
+			Integration:       hub.Integration,
 		})
 		return nil
 	}
@@ -146,6 +163,7 @@ func (c *Catalog) Load(name string, hub *hub.Repository, imageName string, smith
 			}
 		}
 		secrets[secret] = Field{
+			Type:        p.Type,
 			Description: p.Description,
 			Label:       ToLabel(secret),
 			Required:    isRequired,
@@ -168,6 +186,7 @@ func (c *Catalog) Load(name string, hub *hub.Repository, imageName string, smith
 			}
 		}
 		config[name] = Field{
+			Type:        property.Type,
 			Description: property.Description,
 			Label:       ToLabel(name),
 			Required:    isRequired,
@@ -188,14 +207,18 @@ func (c *Catalog) Load(name string, hub *hub.Repository, imageName string, smith
 	}
 
 	artifact := Artifact{
-		Name:            name,
-		Image:           imageName,
-		DisplayName:     hub.DisplayName,
-		Description:     hub.Description,
-		LongDescription: hub.LongDescription,
-		Icon:            hub.Icon,
-		Categories:      hub.Categories,
-		URL:             hub.URL,
+		Name:              name,
+		Image:             imageName,
+		DisplayName:       hub.DisplayName,
+		Description:       hub.Description,
+		LongDescription:   hub.LongDescription,
+		Icon:              hub.Icon,
+		Categories:        hub.Categories,
+		URL:               hub.URL,
+		License:           hub.License,
+		Deprecated:        hub.Deprecated,
+		ReplacedBy:        hub.ReplacedBy,
+		DeprecationNotice: deprecationNotice(hub),
 		Form: Form{
 			Config:  config,
 			Secrets: secrets,
@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+	"github.com/blaxel-ai/mcp-hub/internal/smithery"
+)
+
+func TestLoadSchemaDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smithery.yaml")
+	content := `startCommand:
+  type: stdio
+  configSchema:
+    type: object
+    required:
+      - braveApiKey
+      - region
+    properties:
+      braveApiKey:
+        type: string
+      region:
+        type: string
+        default: eu
+  commandFunction: |-
+    config=>({command:'node',args:['dist/index.js'],env:{BRAVE_API_KEY:config.braveApiKey,BRAVE_REGION:config.region}})
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := smithery.Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Catalog{}
+	if err := c.Load("brave", &hub.Repository{DisplayName: "Brave Search", Secrets: []string{"braveApiKey"}}, "ghcr.io/hub/brave:v1", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	artifact := c.Artifacts[0]
+	if region := artifact.Form.Config["region"]; region.Default != "eu" || !region.Required {
+		t.Errorf("region field %+v, want the required field with the schema default", region)
+	}
+	if apiKey := artifact.Form.Secrets["braveApiKey"]; apiKey.Default != "" {
+		t.Errorf("braveApiKey default = %q, want none", apiKey.Default)
+	}
+	want := map[string]string{"BRAVE_API_KEY": "$braveApiKey", "BRAVE_REGION": "eu"}
+	if !maps.Equal(artifact.Entrypoint.Env, want) {
+		t.Errorf("env = %v, want %v", artifact.Entrypoint.Env, want)
+	}
+	if warnings := smithery.UndeclaredRequired(&cfg, []string{"braveApiKey"}); len(warnings) != 0 {
+		t.Errorf("warnings = %q, want none for a required property with a default", warnings)
+	}
+}
+
+func TestLoadDeprecation(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository hub.Repository
+		want       Artifact
+	}{
+		{
+			name:       "not deprecated",
+			repository: hub.Repository{DisplayName: "Brave Search"},
+			want:       Artifact{},
+		},
+		{
+			name:       "deprecated",
+			repository: hub.Repository{DisplayName: "Brave Search", Deprecated: true},
+			want:       Artifact{Deprecated: true, DeprecationNotice: "Brave Search is deprecated"},
+		},
+		{
+			name:       "replaced",
+			repository: hub.Repository{DisplayName: "Brave Search", Deprecated: true, ReplacedBy: "brave-v2"},
+			want:       Artifact{Deprecated: true, ReplacedBy: "brave-v2", DeprecationNotice: "Brave Search is deprecated, use brave-v2 instead"},
+		},
+		{
+			name:       "replaced and disabled",
+			repository: hub.Repository{DisplayName: "Brave Search", Deprecated: true, ReplacedBy: "brave-v2", Disabled: true},
+			want:       Artifact{Deprecated: true, ReplacedBy: "brave-v2", DeprecationNotice: "Brave Search is deprecated, use brave-v2 instead"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &smithery.SmitheryConfig{ParsedCommand: &smithery.Command{Command: "node"}}
+			c := &Catalog{}
+			if err := c.Load("brave", &tt.repository, "ghcr.io/hub/brave:v1", cfg); err != nil {
+				t.Fatal(err)
+			}
+			got := c.Artifacts[0]
+			if got.Deprecated != tt.want.Deprecated || got.ReplacedBy != tt.want.ReplacedBy || got.DeprecationNotice != tt.want.DeprecationNotice {
+				t.Errorf("got deprecated %t, replaced by %q, notice %q, want %t, %q, %q",
+					got.Deprecated, got.ReplacedBy, got.DeprecationNotice, tt.want.Deprecated, tt.want.ReplacedBy, tt.want.DeprecationNotice)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Changed is an artifact in both catalogs with the top-level fields that differ
+type Changed struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// CatalogDiff lists the artifacts added, removed and changed by publishing the local catalog, sorted by name
+type CatalogDiff struct {
+	Added   []string  `json:"added"`
+	Removed []string  `json:"removed"`
+	Changed []Changed `json:"changed"`
+}
+
+// Empty returns true when publishing the local catalog changes nothing
+func (d *CatalogDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the local and remote artifacts by name, the clone time of their source is ignored as it changes
+// on every import
+func Diff(local, remote map[string]map[string]any) *CatalogDiff {
+	diff := &CatalogDiff{Added: []string{}, Removed: []string{}, Changed: []Changed{}}
+	for _, name := range slices.Sorted(maps.Keys(local)) {
+		remoteArtifact, ok := remote[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		if fields := changedFields(local[name], remoteArtifact); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, Changed{Name: name, Fields: fields})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(remote)) {
+		if _, ok := local[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	return diff
+}
+
+// changedFields returns the sorted top-level fields with a different value in both artifacts
+func changedFields(local, remote map[string]any) []string {
+	local, remote = withoutClonedAt(local), withoutClonedAt(remote)
+	var fields []string
+	for _, key := range slices.Sorted(maps.Keys(local)) {
+		if !reflect.DeepEqual(local[key], remote[key]) {
+			fields = append(fields, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(remote)) {
+		if _, ok := local[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// withoutClonedAt returns a copy of the artifact without source.clonedAt
+func withoutClonedAt(artifact map[string]any) map[string]any {
+	source, ok := artifact["source"].(map[string]any)
+	if !ok {
+		return artifact
+	}
+	artifact = maps.Clone(artifact)
+	source = maps.Clone(source)
+	delete(source, "clonedAt")
+	artifact["source"] = source
+	return artifact
+}
+
+// ReadArtifacts reads the artifacts saved by the file store in dir, by name
+func ReadArtifacts(dir string) (map[string]map[string]any, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	artifacts := make(map[string]map[string]any)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var artifact map[string]any
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		artifacts[strings.TrimSuffix(filepath.Base(path), ".json")] = artifact
+	}
+	return artifacts, nil
+}
+
+// FetchArtifacts returns the artifacts published in the store of the control plane by name, authenticated as the
+// uploader is
+func FetchArtifacts(endpoint, username, password string) (map[string]map[string]any, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/admin/store/mcp", strings.TrimSuffix(endpoint, "/")), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(username, password)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	var published []map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&published); err != nil {
+		return nil, fmt.Errorf("decode published catalog: %w", err)
+	}
+	artifacts := make(map[string]map[string]any)
+	for _, artifact := range published {
+		name, _ := artifact["name"].(string)
+		if name == "" {
+			return nil, errors.New("published artifact without a name")
+		}
+		artifacts[name] = artifact
+	}
+	return artifacts, nil
+}
@@ -0,0 +1,94 @@
+package catalog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// publishedCatalog is the catalog of the stub control plane, brave is unchanged but for its clone time, exa has a
+// new description and notion was removed from the local catalog
+const publishedCatalog = `[
+  {"name": "brave", "image": "ghcr.io/hub/brave:v1", "source": {"commit": "abc", "clonedAt": "2025-01-01T00:00:00Z"}},
+  {"name": "exa", "image": "ghcr.io/hub/exa:v1", "description": "Search."},
+  {"name": "notion", "image": "ghcr.io/hub/notion:v1"}
+]`
+
+// stubControlPlane serves publishedCatalog to the admin user
+func stubControlPlane(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/admin/store/mcp" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		if user, password, _ := r.BasicAuth(); user != "admin" || password != "secret" {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(publishedCatalog))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiffStubRemote(t *testing.T) {
+	dir := t.TempDir()
+	local := map[string]string{
+		"brave.json":  `{"name": "brave", "image": "ghcr.io/hub/brave:v1", "source": {"commit": "abc", "clonedAt": "2025-02-01T00:00:00Z"}}`,
+		"exa.json":    `{"name": "exa", "image": "ghcr.io/hub/exa:v2", "description": "Search the web.", "license": "MIT"}`,
+		"tavily.json": `{"name": "tavily", "image": "ghcr.io/hub/tavily:v1"}`,
+	}
+	for name, content := range local {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	localArtifacts, err := ReadArtifacts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := stubControlPlane(t)
+	remoteArtifacts, err := FetchArtifacts(server.URL+"/", "admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &CatalogDiff{
+		Added:   []string{"tavily"},
+		Removed: []string{"notion"},
+		Changed: []Changed{{Name: "exa", Fields: []string{"description", "image", "license"}}},
+	}
+	if got := Diff(localArtifacts, remoteArtifacts); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+	if diff := Diff(remoteArtifacts, remoteArtifacts); !diff.Empty() {
+		t.Errorf("got %+v, want no changes", diff)
+	}
+}
+
+func TestFetchArtifactsErrors(t *testing.T) {
+	server := stubControlPlane(t)
+	if _, err := FetchArtifacts(server.URL, "admin", "wrong"); err == nil || err.Error() != "HTTP 401: invalid credentials" {
+		t.Errorf("err = %v, want the rejected credentials", err)
+	}
+
+	unnamed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"image": "ghcr.io/hub/brave:v1"}]`))
+	}))
+	defer unnamed.Close()
+	if _, err := FetchArtifacts(unnamed.URL, "admin", "secret"); err == nil || err.Error() != "published artifact without a name" {
+		t.Errorf("err = %v, want the unnamed artifact", err)
+	}
+
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error": "not a list"}`))
+	}))
+	defer invalid.Close()
+	if _, err := FetchArtifacts(invalid.URL, "admin", "secret"); err == nil || !strings.HasPrefix(err.Error(), "decode published catalog: ") {
+		t.Errorf("err = %v, want the decode error", err)
+	}
+}
@@ -0,0 +1,126 @@
+package catalog
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxIconSize bounds the size of an inlined icon, data URIs are stored in the catalog as is
+const maxIconSize = 1 << 20
+
+// iconTypes maps the supported icon extensions to their normalized content type
+var iconTypes = map[string]string{
+	".svg":  "image/svg+xml",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".ico":  "image/x-icon",
+}
+
+var iconClient = &http.Client{Timeout: 30 * time.Second}
+
+// IconType returns the normalized content type of an icon, from the prefix of a data URI,
+// the extension of its URL or the Content-Type of the server
+func IconType(icon string) (string, error) {
+	if strings.HasPrefix(icon, "data:") {
+		mediaType, _, _ := strings.Cut(strings.TrimPrefix(icon, "data:"), ",")
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+		return normalizeIconType(mediaType)
+	}
+	u, err := url.Parse(icon)
+	if err != nil {
+		return "", fmt.Errorf("invalid icon URL %s: %w", icon, err)
+	}
+	if iconType, ok := iconTypes[strings.ToLower(path.Ext(u.Path))]; ok {
+		return iconType, nil
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported icon %s, expected a http(s) URL or a data URI", icon)
+	}
+	resp, err := iconClient.Head(icon)
+	if err != nil {
+		return "", fmt.Errorf("detect icon type of %s: %w", icon, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("detect icon type of %s: HTTP %d", icon, resp.StatusCode)
+	}
+	return normalizeIconType(resp.Header.Get("Content-Type"))
+}
+
+// normalizeIconType returns the content type of a media type when it's a supported icon type
+func normalizeIconType(mediaType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return "", fmt.Errorf("invalid icon type: %w", err)
+	}
+	switch mediaType {
+	case "image/jpg":
+		return "image/jpeg", nil
+	case "image/vnd.microsoft.icon":
+		return "image/x-icon", nil
+	}
+	for _, iconType := range iconTypes {
+		if mediaType == iconType {
+			return iconType, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported icon type %s", mediaType)
+}
+
+// InlineIcon downloads a remote icon and returns it as a base64 data URI, data URIs are returned as is
+func InlineIcon(icon string, iconType string) (string, error) {
+	if strings.HasPrefix(icon, "data:") {
+		return icon, nil
+	}
+	resp, err := iconClient.Get(icon)
+	if err != nil {
+		return "", fmt.Errorf("download icon %s: %w", icon, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download icon %s: HTTP %d", icon, resp.StatusCode)
+	}
+	// Web pages of a code host, such as GitHub blob URLs, end with the extension of the image they show
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return "", fmt.Errorf("icon %s is a web page, use the URL of the raw image", icon)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize+1))
+	if err != nil {
+		return "", fmt.Errorf("download icon %s: %w", icon, err)
+	}
+	if len(data) > maxIconSize {
+		return "", fmt.Errorf("icon %s is larger than %d bytes", icon, maxIconSize)
+	}
+	return fmt.Sprintf("data:%s;base64,%s", iconType, base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// ResolveIcons records the type of the icon of every artifact, and inlines the remote icons when inline is set
+func (c *Catalog) ResolveIcons(inline bool) error {
+	for i := range c.Artifacts {
+		artifact := &c.Artifacts[i]
+		if artifact.Icon == "" {
+			continue
+		}
+		iconType, err := IconType(artifact.Icon)
+		if err != nil {
+			return fmt.Errorf("artifact %s: %w", artifact.Name, err)
+		}
+		artifact.IconType = iconType
+		if inline {
+			if artifact.Icon, err = InlineIcon(artifact.Icon, iconType); err != nil {
+				return fmt.Errorf("artifact %s: %w", artifact.Name, err)
+			}
+		}
+	}
+	return nil
+}
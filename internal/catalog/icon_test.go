@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIconType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logo":
+			w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+		case "/avatar":
+			w.Header().Set("Content-Type", "image/jpg")
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		icon    string
+		want    string
+		wantErr string
+	}{
+		{name: "svg extension", icon: "https://brave.com/logo.svg", want: "image/svg+xml"},
+		{name: "png extension", icon: "https://notion.so/images/Logo.PNG?v=2", want: "image/png"},
+		{name: "png data URI", icon: "data:image/png;base64,iVBORw0KGgo=", want: "image/png"},
+		{name: "svg data URI", icon: "data:image/svg+xml,%3Csvg%2F%3E", want: "image/svg+xml"},
+		{name: "content type of the server", icon: server.URL + "/logo", want: "image/svg+xml"},
+		{name: "normalized content type", icon: server.URL + "/avatar", want: "image/jpeg"},
+		{name: "unsupported data URI", icon: "data:application/pdf;base64,JVBERi0=", wantErr: "unsupported icon type application/pdf"},
+		{name: "unsupported content type", icon: server.URL + "/page", wantErr: "unsupported icon type text/html"},
+		{name: "missing icon", icon: server.URL + "/missing", wantErr: "HTTP 404"},
+		{name: "unsupported scheme", icon: "ftp://brave.com/logo", wantErr: "unsupported icon ftp://brave.com/logo, expected a http(s) URL or a data URI"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IconType(tt.icon)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveIconsInline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blob/logo.png" {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+			return
+		}
+		w.Write([]byte("<svg/>"))
+	}))
+	defer server.Close()
+
+	c := &Catalog{Artifacts: []Artifact{
+		{Name: "brave", Icon: server.URL + "/logo.svg"},
+		{Name: "notion", Icon: "data:image/png;base64,iVBORw0KGgo="},
+		{Name: "local"},
+	}}
+	if err := c.ResolveIcons(true); err != nil {
+		t.Fatal(err)
+	}
+	want := []Artifact{
+		{Name: "brave", Icon: "data:image/svg+xml;base64,PHN2Zy8+", IconType: "image/svg+xml"},
+		{Name: "notion", Icon: "data:image/png;base64,iVBORw0KGgo=", IconType: "image/png"},
+		{Name: "local"},
+	}
+	for i := range want {
+		if got := c.Artifacts[i]; got.Icon != want[i].Icon || got.IconType != want[i].IconType {
+			t.Errorf("artifact %s: got %q of type %q, want %q of type %q", got.Name, got.Icon, got.IconType, want[i].Icon, want[i].IconType)
+		}
+	}
+
+	c = &Catalog{Artifacts: []Artifact{{Name: "github", Icon: server.URL + "/blob/logo.png"}}}
+	if err := c.ResolveIcons(true); err == nil || !strings.Contains(err.Error(), "is a web page, use the URL of the raw image") {
+		t.Errorf("err = %v, want the web page rejected", err)
+	}
+}
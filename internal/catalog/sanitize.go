@@ -0,0 +1,37 @@
+package catalog
+
+import (
+	"regexp"
+)
+
+var (
+	// Elements removed with their content
+	disallowedElements = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)<script\b[^>]*>.*?</script\s*>`),
+		regexp.MustCompile(`(?is)<style\b[^>]*>.*?</style\s*>`),
+		regexp.MustCompile(`(?is)<iframe\b[^>]*>.*?</iframe\s*>`),
+		regexp.MustCompile(`(?is)<object\b[^>]*>.*?</object\s*>`),
+	}
+	// Remaining opening, closing or self-closing tags of disallowed elements
+	disallowedTags  = regexp.MustCompile(`(?is)</?(script|style|iframe|object|embed|form|input|link|meta|base)\b[^>]*>`)
+	eventAttributes = regexp.MustCompile(`(?is)\s+on[a-z]+\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`)
+	javascriptURLs  = regexp.MustCompile(`(?i)javascript:`)
+)
+
+// Sanitize strips the HTML elements and attributes that could run scripts from a Markdown text
+func Sanitize(text string) string {
+	for _, element := range disallowedElements {
+		text = element.ReplaceAllString(text, "")
+	}
+	text = disallowedTags.ReplaceAllString(text, "")
+	text = eventAttributes.ReplaceAllString(text, "")
+	return javascriptURLs.ReplaceAllString(text, "")
+}
+
+// Sanitize sanitizes the descriptions of every artifact of the catalog
+func (c *Catalog) Sanitize() {
+	for i := range c.Artifacts {
+		c.Artifacts[i].Description = Sanitize(c.Artifacts[i].Description)
+		c.Artifacts[i].LongDescription = Sanitize(c.Artifacts[i].LongDescription)
+	}
+}
@@ -0,0 +1,39 @@
+package catalog
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "markdown kept", text: "**Search** the [web](https://brave.com)", want: "**Search** the [web](https://brave.com)"},
+		{name: "script stripped", text: "Search<script>alert(1)</script> the web", want: "Search the web"},
+		{name: "multiline script stripped", text: "a<SCRIPT type=\"text/javascript\">\nfetch(x)\n</script >b", want: "ab"},
+		{name: "event attribute stripped", text: `<img src="logo.png" onerror="alert(1)">`, want: `<img src="logo.png">`},
+		{name: "javascript url stripped", text: "[click](javascript:alert(1))", want: "[click](alert(1))"},
+		{name: "unclosed iframe tag stripped", text: `a<iframe src="https://evil.example.com">b`, want: "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.text); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogSanitize(t *testing.T) {
+	c := Catalog{Artifacts: []Artifact{{
+		Description:     "Brave<script>steal()</script> search",
+		LongDescription: "# Brave\n<script src=\"https://evil.example.com/x.js\"></script>Search the web",
+	}}}
+	c.Sanitize()
+	if got := c.Artifacts[0].Description; got != "Brave search" {
+		t.Errorf("description = %q", got)
+	}
+	if got := c.Artifacts[0].LongDescription; got != "# Brave\nSearch the web" {
+		t.Errorf("long description = %q", got)
+	}
+}
@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed catalog.schema.json
+var embeddedSchema []byte
+
+// schema is the subset of JSON Schema used by the catalog schema
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 schemaTypes        `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *additional        `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	MinLength            int                `json:"minLength"`
+	Pattern              string             `json:"pattern"`
+	Defs                 map[string]*schema `json:"$defs"`
+}
+
+// schemaTypes is the type keyword, a type or a list of types
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// matches returns true when the value has one of the types, integers are numbers
+func (t schemaTypes) matches(value any) bool {
+	valueType := jsonType(value)
+	return slices.Contains(t, valueType) || (valueType == "integer" && slices.Contains(t, "number"))
+}
+
+// additional is the additionalProperties keyword, false to forbid them or the schema of their values
+type additional struct {
+	Allowed bool
+	Schema  *schema
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+var artifactSchema = func() *schema {
+	var s schema
+	if err := json.Unmarshal(embeddedSchema, &s); err != nil {
+		panic(fmt.Sprintf("invalid embedded catalog schema: %v", err))
+	}
+	return &s
+}()
+
+// ValidateArtifact validates the JSON of an artifact against the catalog schema, the error lists every invalid field
+// with its path, e.g. $.form.config.apiKey.label
+func ValidateArtifact(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return errors.Join(artifactSchema.validate("$", value, artifactSchema)...)
+}
+
+func (s *schema) validate(path string, value any, root *schema) []error {
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		if !ok || root.Defs[name] == nil {
+			return []error{fmt.Errorf("%s: unknown schema reference %s", path, s.Ref)}
+		}
+		return root.Defs[name].validate(path, value, root)
+	}
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		return []error{fmt.Errorf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), jsonType(value))}
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, value) {
+		return []error{fmt.Errorf("%s: %v is not one of %v", path, value, s.Enum)}
+	}
+
+	var errs []error
+	switch v := value.(type) {
+	case string:
+		if utf8.RuneCountInString(v) < s.MinLength {
+			errs = append(errs, fmt.Errorf("%s: must be at least %d characters long", path, s.MinLength))
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(v) {
+			errs = append(errs, fmt.Errorf("%s: does not match %s", path, s.Pattern))
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, root)...)
+			}
+		}
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				errs = append(errs, fmt.Errorf("%s.%s: is required", path, key))
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if property, ok := s.Properties[key]; ok {
+				errs = append(errs, property.validate(path+"."+key, v[key], root)...)
+			} else if s.AdditionalProperties != nil && !s.AdditionalProperties.Allowed {
+				errs = append(errs, fmt.Errorf("%s.%s: is not allowed", path, key))
+			} else if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+				errs = append(errs, s.AdditionalProperties.Schema.validate(path+"."+key, v[key], root)...)
+			}
+		}
+	}
+	return errs
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// goldenArtifact returns an artifact with every field set, a field added to Artifact but not to the schema fails
+// its validation
+func goldenArtifact() Artifact {
+	clonedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return Artifact{
+		Name:              "brave",
+		Image:             "ghcr.io/hub/brave:v1",
+		Enterprise:        true,
+		ComingSoon:        true,
+		DisplayName:       "Brave Search",
+		Categories:        []string{"search"},
+		Integration:       "brave-search",
+		Description:       "Search the web using Brave's search engine.",
+		LongDescription:   "Search the web using Brave's search engine.",
+		Icon:              "https://brave.com/logo.svg",
+		IconType:          "image/svg+xml",
+		URL:               "https://brave.com/search/api",
+		License:           "MIT",
+		Deprecated:        true,
+		ReplacedBy:        "brave-v2",
+		DeprecationNotice: "Brave Search is deprecated, use brave-v2 instead",
+		Form: Form{
+			Config:  map[string]Field{"region": {Type: "string", Description: "Region", Label: "Region", Default: "eu"}},
+			Secrets: map[string]Field{"apiKey": {Type: "string", Description: "API key", Label: "API key", Required: true, Hidden: true}},
+			OAuth:   &OAuth{Type: "brave", Scope: []string{"search"}},
+		},
+		HiddenSecrets: []string{"apiKey"},
+		Entrypoint: Entrypoint{
+			Command: "node",
+			Args:    []string{"dist/index.js"},
+			Env:     map[string]string{"BRAVE_API_KEY": "$apiKey"},
+		},
+		Metadata: map[string]string{"stars": "42"},
+		Source: &Source{
+			Repository: "https://github.com/hub/brave.git",
+			Branch:     "main",
+			Commit:     "0123456789abcdef",
+			ClonedAt:   &clonedAt,
+			Path:       "src/brave",
+			Note:       "built from a local path",
+		},
+	}
+}
+
+func TestValidateArtifact(t *testing.T) {
+	golden, err := json.Marshal(goldenArtifact())
+	if err != nil {
+		t.Fatal(err)
+	}
+	minimal, err := json.Marshal(Artifact{Name: "brave", Image: "ghcr.io/hub/brave:v1", DisplayName: "Brave Search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		edit    func(artifact map[string]any)
+		data    []byte
+		wantErr []string
+	}{
+		{name: "golden artifact", data: golden},
+		{name: "minimal artifact", data: minimal},
+		{
+			name:    "missing required field",
+			data:    golden,
+			edit:    func(artifact map[string]any) { delete(artifact, "image") },
+			wantErr: []string{"$.image: is required"},
+		},
+		{
+			name: "invalid nested fields",
+			data: golden,
+			edit: func(artifact map[string]any) {
+				artifact["form"].(map[string]any)["secrets"].(map[string]any)["apiKey"].(map[string]any)["label"] = ""
+				artifact["entrypoint"].(map[string]any)["args"] = "dist/index.js"
+			},
+			wantErr: []string{
+				"$.entrypoint.args: expected array or null, got string",
+				"$.form.secrets.apiKey.label: must be at least 1 characters long",
+			},
+		},
+		{
+			name:    "unknown field",
+			data:    golden,
+			edit:    func(artifact map[string]any) { artifact["version"] = "1" },
+			wantErr: []string{"$.version: is not allowed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			if tt.edit != nil {
+				var artifact map[string]any
+				if err := json.Unmarshal(data, &artifact); err != nil {
+					t.Fatal(err)
+				}
+				tt.edit(artifact)
+				if data, err = json.Marshal(artifact); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := ValidateArtifact(data)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("got %v, want a valid artifact", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != strings.Join(tt.wantErr, "\n") {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveInvalidArtifact(t *testing.T) {
+	artifact := goldenArtifact()
+	artifact.Name = ""
+
+	store := memoryStore{}
+	c := &Catalog{Artifacts: []Artifact{artifact}}
+	if err := c.Save(store); err == nil || err.Error() != "invalid artifact: $.name: must be at least 1 characters long" {
+		t.Errorf("err = %v, want the invalid name", err)
+	}
+	if len(store) != 0 {
+		t.Error("the invalid artifact was saved")
+	}
+
+	c.SkipValidation = true
+	if err := c.Save(store); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store[""]; !ok {
+		t.Error("the artifact was not saved without validation")
+	}
+}
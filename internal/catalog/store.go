@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	StoreAPI  = "api"
+	StoreFile = "file"
+	StoreGCS  = "gcs"
+)
+
+// CatalogStore saves the JSON of the artifacts of the catalog under their name
+type CatalogStore interface {
+	Put(name string, data []byte) error
+}
+
+// NewCatalogStore returns the store registered under the given name, the location is the directory of the file store
+// and the bucket[/prefix] of the gcs store
+func NewCatalogStore(name string, location string) (CatalogStore, error) {
+	switch name {
+	case "", StoreAPI:
+		return NewCatalogUploader(), nil
+	case StoreFile:
+		if location == "" {
+			location = CatalogDir
+		}
+		return &FileStore{Dir: location}, nil
+	case StoreGCS:
+		return NewGCSStore(location, os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"))
+	default:
+		return nil, fmt.Errorf("unsupported catalog store: %s", name)
+	}
+}
+
+// FileStore writes the artifacts to <dir>/<name>.json
+type FileStore struct {
+	Dir string
+}
+
+func (s *FileStore) Put(name string, data []byte) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.Dir, name+".json"), data, 0644)
+}
+
+// GCSStore uploads the artifacts to gs://<bucket>/<prefix>/<name>.json with the JSON API of Google Cloud Storage
+type GCSStore struct {
+	Bucket string
+	Prefix string
+	Token  string
+	Client *http.Client
+}
+
+// NewGCSStore returns a store for the bucket[/prefix] location, authenticated with an OAuth access token,
+// e.g. from gcloud auth print-access-token
+func NewGCSStore(location string, token string) (*GCSStore, error) {
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(location, "gs://"), "/")
+	if bucket == "" {
+		return nil, errors.New("a bucket is required for the gcs catalog store")
+	}
+	if token == "" {
+		return nil, errors.New("GOOGLE_OAUTH_ACCESS_TOKEN is required for the gcs catalog store")
+	}
+	return &GCSStore{Bucket: bucket, Prefix: strings.Trim(prefix, "/"), Token: token, Client: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+// Key returns the object name of an artifact
+func (s *GCSStore) Key(name string) string {
+	if s.Prefix == "" {
+		return name + ".json"
+	}
+	return s.Prefix + "/" + name + ".json"
+}
+
+func (s *GCSStore) Put(name string, data []byte) error {
+	uploadURL := fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s", url.PathEscape(s.Bucket), url.QueryEscape(s.Key(name)))
+	req, err := http.NewRequest("POST", uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
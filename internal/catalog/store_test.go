@@ -0,0 +1,137 @@
+package catalog
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// memoryStore keeps the saved artifacts by name
+type memoryStore map[string][]byte
+
+func (s memoryStore) Put(name string, data []byte) error {
+	s[name] = data
+	return nil
+}
+
+// testCatalog returns a catalog of two artifacts saved without validation
+func testCatalog() *Catalog {
+	return &Catalog{
+		Artifacts:      []Artifact{{Name: "brave", DisplayName: "Brave Search"}, {Name: "notion", DisplayName: "Notion"}},
+		SkipValidation: true,
+	}
+}
+
+func TestSaveMemoryStore(t *testing.T) {
+	store := memoryStore{}
+	if err := testCatalog().Save(store); err != nil {
+		t.Fatal(err)
+	}
+	if keys := slices.Sorted(maps.Keys(store)); !slices.Equal(keys, []string{"brave", "notion"}) {
+		t.Fatalf("keys = %v, want every artifact", keys)
+	}
+	var artifact Artifact
+	if err := json.Unmarshal(store["notion"], &artifact); err != nil {
+		t.Fatal(err)
+	}
+	if artifact.DisplayName != "Notion" {
+		t.Errorf("notion saved as %+v", artifact)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "catalog")
+	if err := testCatalog().Save(&FileStore{Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"brave", "notion"} {
+		if _, err := os.Stat(filepath.Join(dir, name+".json")); err != nil {
+			t.Errorf("artifact %s not written: %v", name, err)
+		}
+	}
+}
+
+// rewriteTransport sends every request to the test server, keeping its path and query
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (r rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = r.target.Scheme, r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGCSStore(t *testing.T) {
+	objects := make(map[string]string)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/upload/storage/v1/b/hub-catalog/o" || r.URL.Query().Get("uploadType") != "media" {
+			t.Errorf("got %s %s", r.Method, r.URL)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("got authorization %q", auth)
+		}
+		body, _ := io.ReadAll(r.Body)
+		objects[r.URL.Query().Get("name")] = string(body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	store, err := NewGCSStore("gs://hub-catalog/v1/", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Client = &http.Client{Transport: rewriteTransport{target: target}}
+	if err := testCatalog().Save(store); err != nil {
+		t.Fatal(err)
+	}
+	if keys := slices.Sorted(maps.Keys(objects)); !slices.Equal(keys, []string{"v1/brave.json", "v1/notion.json"}) {
+		t.Errorf("objects = %v, want every artifact under the prefix", keys)
+	}
+
+	status = http.StatusForbidden
+	if err := store.Put("brave", []byte("{}")); err == nil || err.Error() != "HTTP 403" {
+		t.Errorf("err = %v, want the rejected upload", err)
+	}
+}
+
+func TestNewCatalogStore(t *testing.T) {
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	tests := []struct {
+		name     string
+		store    string
+		location string
+		wantErr  string
+	}{
+		{name: "file", store: StoreFile},
+		{name: "gcs without bucket", store: StoreGCS, location: "gs://", wantErr: "a bucket is required for the gcs catalog store"},
+		{name: "gcs without token", store: StoreGCS, location: "hub-catalog", wantErr: "GOOGLE_OAUTH_ACCESS_TOKEN is required for the gcs catalog store"},
+		{name: "unknown", store: "s3", wantErr: "unsupported catalog store: s3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewCatalogStore(tt.store, tt.location)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fileStore, ok := store.(*FileStore); !ok || fileStore.Dir != CatalogDir {
+				t.Errorf("got %#v, want a file store in %s", store, CatalogDir)
+			}
+		})
+	}
+}
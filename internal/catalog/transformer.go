@@ -0,0 +1,35 @@
+package catalog
+
+import (
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+// CatalogTransformer post-processes the catalog of a repository after it is loaded and before it is saved,
+// e.g. to remap icons to a CDN or add deployment specific metadata
+type CatalogTransformer interface {
+	Transform(repository *hub.Repository, c *Catalog) error
+}
+
+// NoopTransformer leaves the catalog untouched
+type NoopTransformer struct{}
+
+func (NoopTransformer) Transform(repository *hub.Repository, c *Catalog) error {
+	return nil
+}
+
+var transformers []CatalogTransformer
+
+// RegisterTransformer adds a transformer, transformers run in registration order
+func RegisterTransformer(transformer CatalogTransformer) {
+	transformers = append(transformers, transformer)
+}
+
+// Transform runs the registered transformers on the catalog
+func (c *Catalog) Transform(repository *hub.Repository) error {
+	for _, transformer := range transformers {
+		if err := transformer.Transform(repository, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+// pricingTransformer adds the pricing of the repository to the metadata of its artifacts
+type pricingTransformer struct{}
+
+func (pricingTransformer) Transform(repository *hub.Repository, c *Catalog) error {
+	for i := range c.Artifacts {
+		if c.Artifacts[i].Metadata == nil {
+			c.Artifacts[i].Metadata = make(map[string]string)
+		}
+		c.Artifacts[i].Metadata["pricing"] = "free"
+	}
+	return nil
+}
+
+// failingTransformer fails every catalog
+type failingTransformer struct{}
+
+func (failingTransformer) Transform(repository *hub.Repository, c *Catalog) error {
+	return errors.New("pricing service unavailable")
+}
+
+func TestTransform(t *testing.T) {
+	defer func(registered []CatalogTransformer) { transformers = registered }(slices.Clone(transformers))
+	RegisterTransformer(NoopTransformer{})
+	RegisterTransformer(pricingTransformer{})
+
+	repository := &hub.Repository{Disabled: true, DisplayName: "Brave Search"}
+	c := &Catalog{}
+	if err := c.Load("brave", repository, "ghcr.io/hub/brave:v1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Transform(repository); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := c.Save(&FileStore{Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "brave.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Artifact
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Metadata["pricing"] != "free" {
+		t.Errorf("saved metadata %v, want the pricing of the transformer", saved.Metadata)
+	}
+
+	RegisterTransformer(failingTransformer{})
+	if err := c.Transform(repository); err == nil || err.Error() != "pricing service unavailable" {
+		t.Errorf("err = %v, want the error of the transformer", err)
+	}
+}
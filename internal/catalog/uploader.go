@@ -0,0 +1,92 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// StatusError is returned when the control plane rejects an artifact
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// Retriable returns true for the server errors and rate limits, client errors will fail again
+func (e *StatusError) Retriable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// CatalogUploader uploads the artifacts to the store of the control plane, retrying the transient failures
+type CatalogUploader struct {
+	Endpoint string
+	Username string
+	Password string
+	Client   *http.Client
+	// Retries is the number of retries after the first attempt
+	Retries int
+	// Backoff is the wait before the first retry, doubled on every retry
+	Backoff time.Duration
+}
+
+// NewCatalogUploader returns an uploader configured by BL_API_URL, BL_ADMIN_USERNAME and BL_ADMIN_PASSWORD
+func NewCatalogUploader() *CatalogUploader {
+	return &CatalogUploader{
+		Endpoint: os.Getenv("BL_API_URL"),
+		Username: os.Getenv("BL_ADMIN_USERNAME"),
+		Password: os.Getenv("BL_ADMIN_PASSWORD"),
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		Retries:  3,
+		Backoff:  time.Second,
+	}
+}
+
+// Put uploads the artifact to the store of the control plane, server errors are retried with an exponential backoff
+func (u *CatalogUploader) Put(name string, data []byte) error {
+	backoff := u.Backoff
+	for attempt := 0; ; attempt++ {
+		err := u.put(name, data)
+		var statusErr *StatusError
+		if err == nil || (errors.As(err, &statusErr) && !statusErr.Retriable()) || attempt >= u.Retries {
+			return err
+		}
+		fmt.Printf("failed to upload artifact %s, retrying in %s: %s\n", name, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (u *CatalogUploader) put(name string, jsonData []byte) error {
+	url := fmt.Sprintf("%s/admin/store/mcp/%s", u.Endpoint, name)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(u.Username, u.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
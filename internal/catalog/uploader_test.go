@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCatalogUploaderPut(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		wantErr  bool
+		attempts int
+	}{
+		{name: "success", statuses: []int{http.StatusOK}, attempts: 1},
+		{name: "server error retried", statuses: []int{http.StatusBadGateway, http.StatusOK}, attempts: 2},
+		{name: "rate limit retried", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, attempts: 2},
+		{name: "client error not retried", statuses: []int{http.StatusBadRequest}, wantErr: true, attempts: 1},
+		{name: "retries exhausted", statuses: []int{500, 500, 500, 500}, wantErr: true, attempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "PUT" || r.URL.Path != "/admin/store/mcp/brave" {
+					t.Errorf("got %s %s", r.Method, r.URL.Path)
+				}
+				if user, password, _ := r.BasicAuth(); user != "admin" || password != "secret" {
+					t.Errorf("got credentials %s:%s", user, password)
+				}
+				w.WriteHeader(tt.statuses[min(attempts, len(tt.statuses)-1)])
+				attempts++
+			}))
+			defer server.Close()
+
+			uploader := &CatalogUploader{Endpoint: server.URL, Username: "admin", Password: "secret", Client: server.Client(), Retries: 2}
+			err := uploader.Put("brave", []byte("{}"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			var statusErr *StatusError
+			if tt.wantErr && !errors.As(err, &statusErr) {
+				t.Errorf("err = %v, want a StatusError", err)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("%d attempts, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
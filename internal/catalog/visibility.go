@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+// FilterVisibility removes what the visibility can't see from the catalog of a repository:
+// the public catalog has neither the internal repositories nor the internal fields
+func (c *Catalog) FilterVisibility(visibility string, repository *hub.Repository) error {
+	switch visibility {
+	case hub.VisibilityInternal:
+		return nil
+	case hub.VisibilityPublic:
+	default:
+		return fmt.Errorf("unknown visibility %s, expected %s or %s", visibility, hub.VisibilityPublic, hub.VisibilityInternal)
+	}
+	if repository.Visibility == hub.VisibilityInternal {
+		c.Artifacts = nil
+		return nil
+	}
+	for i := range c.Artifacts {
+		for _, field := range repository.InternalFields {
+			if err := c.Artifacts[i].clearField(field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// clearField resets a field of the artifact by its json name, metadata.<key> removes a metadata key
+func (a *Artifact) clearField(name string) error {
+	if key, ok := strings.CutPrefix(name, "metadata."); ok {
+		delete(a.Metadata, key)
+		return nil
+	}
+	v := reflect.ValueOf(a).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name && !slices.Contains([]string{"name", "image"}, tag) {
+			v.Field(i).SetZero()
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown internal field %s", name)
+}
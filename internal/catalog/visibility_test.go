@@ -0,0 +1,80 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+func TestFilterVisibility(t *testing.T) {
+	artifact := func() Artifact {
+		return Artifact{
+			Name:        "brave",
+			Image:       "ghcr.io/hub/brave:v1",
+			URL:         "https://wiki.internal/brave",
+			Description: "Search the web.",
+			Metadata:    map[string]string{"owner": "search-team", "pricing": "free"},
+		}
+	}
+	tests := []struct {
+		name       string
+		visibility string
+		repository hub.Repository
+		want       *Artifact
+		wantErr    string
+	}{
+		{
+			name:       "internal catalog keeps everything",
+			visibility: hub.VisibilityInternal,
+			repository: hub.Repository{Visibility: hub.VisibilityInternal, InternalFields: []string{"url"}},
+			want:       &Artifact{Name: "brave", Image: "ghcr.io/hub/brave:v1", URL: "https://wiki.internal/brave", Description: "Search the web.", Metadata: map[string]string{"owner": "search-team", "pricing": "free"}},
+		},
+		{
+			name:       "public catalog leaves out internal repositories",
+			visibility: hub.VisibilityPublic,
+			repository: hub.Repository{Visibility: hub.VisibilityInternal},
+		},
+		{
+			name:       "public catalog clears internal fields",
+			visibility: hub.VisibilityPublic,
+			repository: hub.Repository{Visibility: hub.VisibilityPublic, InternalFields: []string{"url", "metadata.owner"}},
+			want:       &Artifact{Name: "brave", Image: "ghcr.io/hub/brave:v1", Description: "Search the web.", Metadata: map[string]string{"pricing": "free"}},
+		},
+		{
+			name:       "name is never cleared",
+			visibility: hub.VisibilityPublic,
+			repository: hub.Repository{Visibility: hub.VisibilityPublic, InternalFields: []string{"name"}},
+			wantErr:    "unknown internal field name",
+		},
+		{
+			name:       "unknown visibility",
+			visibility: "private",
+			wantErr:    "unknown visibility private, expected public or internal",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Catalog{Artifacts: []Artifact{artifact()}}
+			err := c.FilterVisibility(tt.visibility, &tt.repository)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == nil {
+				if len(c.Artifacts) != 0 {
+					t.Errorf("got %d artifacts, want none", len(c.Artifacts))
+				}
+				return
+			}
+			if !reflect.DeepEqual(c.Artifacts[0], *tt.want) {
+				t.Errorf("got  %+v\nwant %+v", c.Artifacts[0], *tt.want)
+			}
+		})
+	}
+}
@@ -3,13 +3,50 @@ package docker
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 )
 
-func BuildImage(ctx context.Context, imageName string, smitheryPath string, dockerfileDir string, dockerfilePath string) (string, error) {
+// CatalogLabel is the label holding the catalog artifact of the images built by the hub, so a prebuilt image can be
+// run without cloning its repository
+const CatalogLabel = "hub.catalog"
+
+type BuildOptions struct {
+	Ignore    []string
+	BuildArgs map[string]string
+	Labels    map[string]string
+	// Context is the build context directory when it's not the directory of the Dockerfile, e.g. the repository root
+	Context string
+	// Target is the stage of a multi-stage Dockerfile to build, the last one when empty
+	Target string
+	// Network is the network of the RUN instructions, default, none, host or a network name, the daemon default when empty
+	Network string
+	// Platforms are the platforms to build the image for, the platform of the daemon when empty
+	Platforms []string
+	// Pull pulls the base images before building to fail fast on bad references
+	Pull bool
+	// Lint statically checks the Dockerfile against the build context before building
+	Lint bool
+	// ExtraFiles are host files copied into the build context, a map of destination to source
+	ExtraFiles map[string]string
+	// SourceDateEpoch is the unix timestamp of the source commit, when set the timestamps of the image are
+	// set to it so rebuilds of the same commit are identical
+	SourceDateEpoch int64
+	// PreHooks and PostHooks are shell commands run in the repository directory before and after the build
+	PreHooks  []string
+	PostHooks []string
+	// MaxSize fails the build when the image is larger, in bytes, not checked when 0
+	MaxSize int64
+	// CacheRef is the registry reference used to import and export the BuildKit cache, disabled when empty
+	CacheRef string
+}
+
+func BuildImage(ctx context.Context, imageNames []string, smitheryPath string, dockerfileDir string, dockerfilePath string, opts BuildOptions) (string, error) {
 	directory := filepath.Dir(dockerfilePath)
 	dockerfile := filepath.Base(dockerfilePath)
 
@@ -19,15 +56,182 @@ func BuildImage(ctx context.Context, imageName string, smitheryPath string, dock
 	if dockerfileDir != "" && strings.Contains(dockerfileDir, "/") && dockerfileDir != "/" {
 		dockerfile = fmt.Sprintf("%s/%s", dockerfileDir, dockerfile)
 	}
+	builtDockerfile := directory + "/" + dockerfile
+	if opts.Context != "" {
+		var err error
+		if directory, dockerfile, err = contextDockerfile(opts.Context, builtDockerfile); err != nil {
+			return "", err
+		}
+	}
+
+	restoreDockerignore, err := WriteDockerignore(directory, opts.Ignore)
+	if err != nil {
+		return "", err
+	}
+	defer restoreDockerignore()
+
+	removeExtraFiles, err := CopyExtraFiles(directory, opts.ExtraFiles)
+	if err != nil {
+		return "", err
+	}
+	defer removeExtraFiles()
+
+	if opts.Lint {
+		if err := LintDockerfile(builtDockerfile, directory); err != nil {
+			return "", err
+		}
+	}
+	if opts.Pull {
+		if err := PullBaseImages(ctx, builtDockerfile); err != nil {
+			return "", err
+		}
+	}
 
-	fmt.Println("Building image", imageName, "with smitheryPath", smitheryPath, "with dockerfile", dockerfile, "in directory", directory)
-	cmd := exec.Command("docker", "build", "-t", imageName, "-f", dockerfile, ".")
+	fmt.Println("Building image", strings.Join(imageNames, ", "), "with smitheryPath", smitheryPath, "with dockerfile", dockerfile, "in directory", directory)
+	args := BuildArgs(imageNames, dockerfile, opts)
+	fmt.Println("Running docker", strings.Join(RedactArgs(args, nil), " "))
+	cmd := exec.Command("docker", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Dir = directory
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		return "", err
 	}
-	return directory + "/" + dockerfile, nil
+	if opts.MaxSize > 0 {
+		if err := checkImageSize(ctx, imageNames[0], opts); err != nil {
+			return "", err
+		}
+	}
+	return builtDockerfile, nil
+}
+
+// contextDockerfile returns the context directory and the Dockerfile path passed to -f, relative to the context
+// when it's inside of it
+func contextDockerfile(context string, dockerfilePath string) (string, string, error) {
+	info, err := os.Stat(context)
+	if err != nil {
+		return "", "", fmt.Errorf("build context: %w", err)
+	}
+	if !info.IsDir() {
+		return "", "", fmt.Errorf("build context %s is not a directory", context)
+	}
+	if _, err := os.Stat(dockerfilePath); err != nil {
+		return "", "", fmt.Errorf("dockerfile: %w", err)
+	}
+	absDockerfile, err := filepath.Abs(dockerfilePath)
+	if err != nil {
+		return "", "", err
+	}
+	absContext, err := filepath.Abs(context)
+	if err != nil {
+		return "", "", err
+	}
+	if rel, err := filepath.Rel(absContext, absDockerfile); err == nil && filepath.IsLocal(rel) {
+		return context, filepath.ToSlash(rel), nil
+	}
+	return context, absDockerfile, nil
+}
+
+// BuildArgs returns the arguments of the docker build command
+func BuildArgs(imageNames []string, dockerfile string, opts BuildOptions) []string {
+	args := []string{"build"}
+	for _, imageName := range imageNames {
+		args = append(args, "-t", imageName)
+	}
+	args = append(args, "-f", dockerfile)
+	buildArgs := maps.Clone(opts.BuildArgs)
+	if opts.SourceDateEpoch > 0 {
+		if buildArgs == nil {
+			buildArgs = make(map[string]string)
+		}
+		buildArgs["SOURCE_DATE_EPOCH"] = strconv.FormatInt(opts.SourceDateEpoch, 10)
+	}
+	for _, key := range slices.Sorted(maps.Keys(buildArgs)) {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, buildArgs[key]))
+	}
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+	if opts.Network != "" {
+		args = append(args, "--network", opts.Network)
+	}
+	if len(opts.Platforms) > 0 {
+		args = append(args, "--platform", strings.Join(opts.Platforms, ","))
+	}
+	for _, key := range slices.Sorted(maps.Keys(opts.Labels)) {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, opts.Labels[key]))
+	}
+	if opts.CacheRef != "" {
+		args = append(args,
+			"--cache-from", fmt.Sprintf("type=registry,ref=%s", opts.CacheRef),
+			"--cache-to", fmt.Sprintf("type=registry,ref=%s,mode=max", opts.CacheRef),
+		)
+	}
+	// The docker output loads the image, as --load does for the cache
+	if opts.SourceDateEpoch > 0 {
+		args = append(args, "--output", "type=docker,rewrite-timestamp=true")
+	} else if opts.CacheRef != "" {
+		args = append(args, "--load")
+	}
+	return append(args, ".")
+}
+
+// CacheRef returns the reference of the build cache of an image: registry/name:tag -> registry/name:buildcache
+func CacheRef(imageName string) string {
+	name := imageName
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name + ":buildcache"
+}
+
+// checkImageSize fails when the built image is larger than the maximum size of the options
+func checkImageSize(ctx context.Context, imageName string, opts BuildOptions) error {
+	if len(opts.Platforms) > 1 {
+		// Multi-platform images are not loaded in the daemon
+		fmt.Printf("Warning: the size of the multi-platform image %s is not checked\n", imageName)
+		return nil
+	}
+	size, err := ImageSize(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	if size > opts.MaxSize {
+		return fmt.Errorf("image %s is %s, larger than the maximum of %s", imageName, formatSize(size), formatSize(opts.MaxSize))
+	}
+	return nil
+}
+
+// formatSize returns a size in bytes in MiB
+func formatSize(size int64) string {
+	return fmt.Sprintf("%.1fMiB", float64(size)/(1<<20))
+}
+
+// ImageSize returns the size in bytes of an image of the local docker daemon
+func ImageSize(ctx context.Context, imageName string) (int64, error) {
+	output, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{.Size}}", imageName).Output()
+	if err != nil {
+		return 0, fmt.Errorf("inspect image %s: %w", imageName, err)
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+}
+
+// ImageLabel returns the value of a label of a local image, empty when the image doesn't have it
+func ImageLabel(ctx context.Context, imageName string, label string) (string, error) {
+	format := fmt.Sprintf("{{index .Config.Labels %q}}", label)
+	output, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", format, imageName).Output()
+	if err != nil {
+		return "", fmt.Errorf("inspect image %s: %w", imageName, err)
+	}
+	value := strings.TrimSpace(string(output))
+	if value == "<no value>" {
+		return "", nil
+	}
+	return value, nil
+}
+
+// ImageExists returns true when the image is present in the local docker daemon
+func ImageExists(ctx context.Context, imageName string) bool {
+	return exec.CommandContext(ctx, "docker", "image", "inspect", imageName).Run() == nil
 }
@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildArgs(t *testing.T) {
+	imageNames := []string{"ghcr.io/hub/brave:v1", "ghcr.io/hub/brave:latest"}
+	tests := []struct {
+		name string
+		opts BuildOptions
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{"build", "-t", "ghcr.io/hub/brave:v1", "-t", "ghcr.io/hub/brave:latest", "-f", "Dockerfile", "."},
+		},
+		{
+			name: "build args and labels sorted",
+			opts: BuildOptions{
+				BuildArgs: map[string]string{"HTTPS_PROXY": "http://proxy:3128", "HTTP_PROXY": "http://proxy:3128"},
+				Labels:    map[string]string{"hub.name": "brave", "hub.enterprise": "true"},
+			},
+			want: []string{"build", "-t", "ghcr.io/hub/brave:v1", "-t", "ghcr.io/hub/brave:latest", "-f", "Dockerfile",
+				"--build-arg", "HTTPS_PROXY=http://proxy:3128", "--build-arg", "HTTP_PROXY=http://proxy:3128",
+				"--label", "hub.enterprise=true", "--label", "hub.name=brave", "."},
+		},
+		{
+			name: "target stage",
+			opts: BuildOptions{Target: "runtime"},
+			want: []string{"build", "-t", "ghcr.io/hub/brave:v1", "-t", "ghcr.io/hub/brave:latest", "-f", "Dockerfile", "--target", "runtime", "."},
+		},
+		{
+			name: "network",
+			opts: BuildOptions{Network: "none", Target: "runtime"},
+			want: []string{"build", "-t", "ghcr.io/hub/brave:v1", "-t", "ghcr.io/hub/brave:latest", "-f", "Dockerfile", "--target", "runtime", "--network", "none", "."},
+		},
+		{
+			name: "named network",
+			opts: BuildOptions{Network: "private-registry"},
+			want: []string{"build", "-t", "ghcr.io/hub/brave:v1", "-t", "ghcr.io/hub/brave:latest", "-f", "Dockerfile", "--network", "private-registry", "."},
+		},
+		{
+			name: "reproducible",
+			opts: BuildOptions{SourceDateEpoch: 1709294400, BuildArgs: map[string]string{"NODE_ENV": "production"}},
+			want: []string{"build", "-t", "ghcr.io/hub/brave:v1", "-t", "ghcr.io/hub/brave:latest", "-f", "Dockerfile",
+				"--build-arg", "NODE_ENV=production", "--build-arg", "SOURCE_DATE_EPOCH=1709294400",
+				"--output", "type=docker,rewrite-timestamp=true", "."},
+		},
+		{
+			name: "registry cache",
+			opts: BuildOptions{CacheRef: "ghcr.io/hub/brave:buildcache"},
+			want: []string{"build", "-t", "ghcr.io/hub/brave:v1", "-t", "ghcr.io/hub/brave:latest", "-f", "Dockerfile",
+				"--cache-from", "type=registry,ref=ghcr.io/hub/brave:buildcache",
+				"--cache-to", "type=registry,ref=ghcr.io/hub/brave:buildcache,mode=max", "--load", "."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildArgs(imageNames, "Dockerfile", tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("got  %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheRef(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io/hub/brave:v1":         "ghcr.io/hub/brave:buildcache",
+		"ghcr.io/hub/brave":            "ghcr.io/hub/brave:buildcache",
+		"localhost:5000/hub/brave:dev": "localhost:5000/hub/brave:buildcache",
+		"localhost:5000/hub/brave":     "localhost:5000/hub/brave:buildcache",
+	}
+	for imageName, want := range tests {
+		if got := CacheRef(imageName); got != want {
+			t.Errorf("CacheRef(%s) = %s, want %s", imageName, got, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestContextDockerfile(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "src", "brave"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dockerfilePath := filepath.Join(repo, "src", "brave", "Dockerfile")
+	if err := os.WriteFile(dockerfilePath, []byte("FROM node:22-alpine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name           string
+		context        string
+		dockerfilePath string
+		wantDockerfile string
+		wantErr        string
+	}{
+		{
+			name:           "dockerfile in a subdirectory of the context",
+			context:        repo,
+			dockerfilePath: dockerfilePath,
+			wantDockerfile: "src/brave/Dockerfile",
+		},
+		{
+			name:           "context path not cleaned",
+			context:        filepath.Join(repo, "src", "brave", ".."),
+			dockerfilePath: filepath.Join(repo, "src", "brave", "Dockerfile"),
+			wantDockerfile: "brave/Dockerfile",
+		},
+		{
+			name:           "dockerfile next to the context",
+			context:        filepath.Join(repo, "src", "brave", "Dockerfile"),
+			dockerfilePath: dockerfilePath,
+			wantErr:        "build context " + dockerfilePath + " is not a directory",
+		},
+		{
+			name:           "missing context",
+			context:        filepath.Join(repo, "shared"),
+			dockerfilePath: dockerfilePath,
+			wantErr:        "build context: ",
+		},
+		{
+			name:           "missing dockerfile",
+			context:        repo,
+			dockerfilePath: filepath.Join(repo, "Dockerfile"),
+			wantErr:        "dockerfile: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory, dockerfile, err := contextDockerfile(tt.context, tt.dockerfilePath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if directory != tt.context || dockerfile != tt.wantDockerfile {
+				t.Errorf("got %s -f %s, want %s -f %s", directory, dockerfile, tt.context, tt.wantDockerfile)
+			}
+		})
+	}
+
+	outside := filepath.Join(t.TempDir(), "Dockerfile")
+	if err := os.WriteFile(outside, []byte("FROM node:22-alpine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, dockerfile, err := contextDockerfile(repo, outside); err != nil || dockerfile != outside {
+		t.Errorf("got -f %s (%v), want the absolute path of a Dockerfile outside of the context", dockerfile, err)
+	}
+}
+
+func TestBuildImageContext(t *testing.T) {
+	workdir := filepath.Join(t.TempDir(), "workdir")
+	// The context is the working directory of docker, the build args end with .
+	dockerLog := fakeTool(t, "docker", `if [ "$1" = build ]; then pwd > `+workdir+`; fi`)
+
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "src", "brave"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dockerfilePath := filepath.Join(repo, "src", "brave", "Dockerfile")
+	if err := os.WriteFile(dockerfilePath, []byte("FROM node:22-alpine\nCOPY shared /shared\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	built, err := BuildImage(context.Background(), []string{"registry.test/brave:v1"}, "", "", dockerfilePath, BuildOptions{Context: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if built != dockerfilePath {
+		t.Errorf("built dockerfile %s, want %s", built, dockerfilePath)
+	}
+	want := []string{"build -t registry.test/brave:v1 -f src/brave/Dockerfile ."}
+	if calls := toolCalls(t, dockerLog); !slices.Equal(calls, want) {
+		t.Errorf("docker calls %q, want %q", calls, want)
+	}
+	content, err := os.ReadFile(workdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := filepath.EvalSymlinks(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(content)); got != resolved {
+		t.Errorf("build context %s, want the repository root %s", got, resolved)
+	}
+}
@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CopyExtraFiles copies the host files to their destination in the build context, a map of destination to source.
+// Existing files of the context are never overwritten. The returned function removes the copied files
+// and must be called once the build is done.
+func CopyExtraFiles(directory string, files map[string]string) (func() error, error) {
+	var copied []string
+	remove := func() error {
+		for _, path := range copied {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, dst := range slices.Sorted(maps.Keys(files)) {
+		path := filepath.Join(directory, dst)
+		if rel, err := filepath.Rel(directory, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			remove()
+			return nil, fmt.Errorf("extra file %s is outside of the build context", dst)
+		}
+		if _, err := os.Stat(path); err == nil {
+			remove()
+			return nil, fmt.Errorf("extra file %s already exists in the build context", dst)
+		}
+		if err := copyFile(files[dst], path); err != nil {
+			remove()
+			return nil, fmt.Errorf("copy extra file %s: %w", dst, err)
+		}
+		copied = append(copied, path)
+	}
+	return remove, nil
+}
+
+// copyFile copies src to dst, creating the directories of dst
+func copyFile(src string, dst string) error {
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	destination, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		return err
+	}
+	return destination.Close()
+}
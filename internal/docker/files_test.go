@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildImageExtraFiles(t *testing.T) {
+	host := t.TempDir()
+	caPath := filepath.Join(host, "ca.pem")
+	if err := os.WriteFile(caPath, []byte("-----BEGIN CERTIFICATE-----"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	seen := filepath.Join(t.TempDir(), "seen.pem")
+	// The fake build reads the extra file from its context, the working directory of docker
+	fakeTool(t, "docker", `if [ "$1" = build ]; then cp certs/ca.pem `+seen+`; fi`)
+
+	dir := t.TempDir()
+	dockerfilePath := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(dockerfilePath, []byte("FROM node:22-alpine\nCOPY certs/ca.pem /etc/ssl/certs/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := BuildOptions{ExtraFiles: map[string]string{"certs/ca.pem": caPath}}
+	if _, err := BuildImage(context.Background(), []string{"registry.test/brave:v1"}, "", "", dockerfilePath, opts); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(seen)
+	if err != nil {
+		t.Fatalf("extra file not in the build context: %v", err)
+	}
+	if string(content) != "-----BEGIN CERTIFICATE-----" {
+		t.Errorf("build context file = %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "certs", "ca.pem")); !os.IsNotExist(err) {
+		t.Error("extra file left in the repository after the build")
+	}
+}
+
+func TestCopyExtraFilesErrors(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(source, []byte("ca"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ca.pem"), []byte("repository"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "existing file", files: map[string]string{"a.pem": source, "ca.pem": source}, want: "extra file ca.pem already exists in the build context"},
+		{name: "outside of the context", files: map[string]string{"../ca.pem": source}, want: "extra file ../ca.pem is outside of the build context"},
+		{name: "missing source", files: map[string]string{"a.pem": source + ".missing"}, want: "copy extra file a.pem: open "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CopyExtraFiles(dir, tt.files)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "a.pem")); !os.IsNotExist(err) {
+				t.Error("files copied before the failure are not removed")
+			}
+			if content, _ := os.ReadFile(filepath.Join(dir, "ca.pem")); string(content) != "repository" {
+				t.Errorf("repository file overwritten: %q", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// RunHooks runs the shell commands of a build hook in dir one after the other, the first failure stops them.
+// The hooks are trusted config, they are not sandboxed beyond running in the repository directory.
+func RunHooks(ctx context.Context, stage string, dir string, hooks []string) error {
+	for _, hook := range hooks {
+		fmt.Printf("Running %s hook in %s: %s\n", stage, dir, hook)
+		cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if len(output) > 0 {
+			fmt.Print(string(output))
+		}
+		if err != nil {
+			return fmt.Errorf("%s hook %q failed: %w: %s", stage, hook, err, strings.TrimSpace(lastLines(string(output), 20)))
+		}
+	}
+	return nil
+}
+
+// lastLines returns the last n lines of the output of a command
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
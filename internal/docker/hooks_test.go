@@ -0,0 +1,32 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunHooks(t *testing.T) {
+	dir := t.TempDir()
+	err := RunHooks(context.Background(), "pre-build", dir, []string{"echo one > hooks.log", "echo two >> hooks.log"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "hooks.log"))
+	if err != nil || string(content) != "one\ntwo\n" {
+		t.Errorf("hooks.log = %q, %v, want both hooks run in order in the directory", content, err)
+	}
+
+	err = RunHooks(context.Background(), "post-build", dir, []string{"echo checking; exit 3", "touch never"})
+	if err == nil || err.Error() != `post-build hook "echo checking; exit 3" failed: exit status 3: checking` {
+		t.Errorf("err = %v, want the failed hook with its output", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never")); !os.IsNotExist(err) {
+		t.Error("a hook ran after the failure")
+	}
+	if got := lastLines(strings.Repeat("line\n", 30)+"last\n", 2); got != "line\nlast" {
+		t.Errorf("lastLines = %q", got)
+	}
+}
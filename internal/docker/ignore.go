@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const dockerignore = ".dockerignore"
+
+// defaultIgnore are excluded from every build context, they are never needed by the images
+var defaultIgnore = []string{
+	".git",
+	"node_modules",
+	"**/node_modules",
+	".venv",
+	"**/__pycache__",
+}
+
+// WriteDockerignore writes the .dockerignore of the build context, merging with the existing one if any.
+// Our patterns are written first so the repository rules, including negations, keep precedence.
+// The returned function restores the original file and must be called once the build is done.
+func WriteDockerignore(directory string, patterns []string) (func() error, error) {
+	path := filepath.Join(directory, dockerignore)
+	existing, err := os.ReadFile(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	content := MergeDockerignore(string(existing), append(slices.Clone(defaultIgnore), patterns...))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return func() error {
+		if exists {
+			return os.WriteFile(path, existing, 0644)
+		}
+		return os.Remove(path)
+	}, nil
+}
+
+// MergeDockerignore returns the content of a .dockerignore with the patterns missing from existing prepended to it
+func MergeDockerignore(existing string, patterns []string) string {
+	present := make(map[string]bool)
+	for _, line := range strings.Split(existing, "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	var lines []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || present[pattern] {
+			continue
+		}
+		present[pattern] = true
+		lines = append(lines, pattern)
+	}
+	if len(lines) == 0 {
+		return existing
+	}
+
+	lines = append([]string{"# Added by mcp-hub"}, lines...)
+	if existing != "" {
+		lines = append(lines, "", existing)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
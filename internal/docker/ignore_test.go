@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteDockerignore(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *string
+		want     string
+	}{
+		{
+			name: "no dockerignore",
+			want: "# Added by mcp-hub\n.git\nnode_modules\n**/node_modules\n.venv\n**/__pycache__\ncoverage\n",
+		},
+		{
+			name:     "existing dockerignore keeps its rules last",
+			existing: ptr("node_modules\n!node_modules/keep\n"),
+			want:     "# Added by mcp-hub\n.git\n**/node_modules\n.venv\n**/__pycache__\ncoverage\n\nnode_modules\n!node_modules/keep\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, dockerignore)
+			if tt.existing != nil {
+				if err := os.WriteFile(path, []byte(*tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			restore, err := WriteDockerignore(dir, []string{"coverage"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("content = %q, want %q", content, tt.want)
+			}
+
+			if err := restore(); err != nil {
+				t.Fatal(err)
+			}
+			restored, err := os.ReadFile(path)
+			switch {
+			case tt.existing == nil && !os.IsNotExist(err):
+				t.Errorf("generated .dockerignore not removed: %v", err)
+			case tt.existing != nil && string(restored) != *tt.existing:
+				t.Errorf("restored content = %q, want %q", restored, *tt.existing)
+			}
+		})
+	}
+}
+
+func TestMergeDockerignoreNothingToAdd(t *testing.T) {
+	existing := ".git\nnode_modules\n"
+	if got := MergeDockerignore(existing, []string{".git", " node_modules ", ""}); got != existing {
+		t.Errorf("got %q, want the existing content unchanged", got)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
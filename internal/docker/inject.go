@@ -5,16 +5,55 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
-func Inject(ctx context.Context, name string, path string, smitheryDir string, dockerfileDir string, cmd string, deps []string) (string, error) {
-	dockerFilePath := filepath.Join(path, smitheryDir, dockerfileDir)
+const (
+	// HubDockerfile is the dockerfile value of repositories built from a template of the dockerfiles directory
+	HubDockerfile = "@mcp-hub"
+	templatesDir  = "dockerfiles"
+)
+
+// TemplatePath returns the path of the hub Dockerfile template of a repository
+func TemplatePath(name string) string {
+	return filepath.Join(templatesDir, fmt.Sprintf("%s.Dockerfile", strings.ToLower(name)))
+}
+
+// CheckTemplate returns an actionable error when the hub Dockerfile template of a repository is missing
+func CheckTemplate(name string) error {
+	templatePath := TemplatePath(name)
+	if _, err := os.Stat(templatePath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("missing Dockerfile template %s for repository %s using dockerfile %q, add the template or set dockerfile to the repository Dockerfile", templatePath, name, HubDockerfile)
+		}
+		return err
+	}
+	return nil
+}
+
+// SplitDockerfile splits the dockerfile of a repository into its directory and file name,
+// "/" and a bare file name are at the root of the repository
+func SplitDockerfile(dockerfile string) (string, string) {
+	if dockerfile == HubDockerfile || dockerfile == "/" {
+		return dockerfile, "Dockerfile"
+	}
+	dir, file := path.Split(strings.TrimSuffix(dockerfile, "/"))
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		dir = "/"
+	}
+	return dir, file
+}
+
+func Inject(ctx context.Context, name string, repoPath string, smitheryDir string, dockerfileDir string, cmd string, deps []string, target string) (string, error) {
+	dockerFilePath := filepath.Join(repoPath, smitheryDir, dockerfileDir)
 	os.Remove(fmt.Sprintf("%s.tmp", dockerFilePath))
-	if smitheryDir == "@mcp-hub" {
+	if smitheryDir == HubDockerfile {
 		// Use the current working directory to construct the full path to the source file
-		sourcePath := filepath.Join("dockerfiles", fmt.Sprintf("%s.Dockerfile", strings.ToLower(name)))
+		sourcePath := TemplatePath(name)
 
 		// Open source file
 		sourceFile, err := os.Open(sourcePath)
@@ -24,7 +63,7 @@ func Inject(ctx context.Context, name string, path string, smitheryDir string, d
 		defer sourceFile.Close()
 
 		// Create destination file
-		destPath := filepath.Join(path, "Dockerfile.tmp")
+		destPath := filepath.Join(repoPath, "Dockerfile.tmp")
 		destFile, err := os.Create(destPath)
 		if err != nil {
 			return "", fmt.Errorf("failed to create destination file: %w", err)
@@ -60,11 +99,53 @@ func Inject(ctx context.Context, name string, path string, smitheryDir string, d
 		}
 		lines = append(lines, line)
 	}
-	lines[len(lines)-1] = ""
+	var injected []string
 	for _, dep := range deps {
-		lines = append(lines, fmt.Sprintf("RUN %s", dep))
+		injected = append(injected, fmt.Sprintf("RUN %s", dep))
+	}
+	injected = append(injected, fmt.Sprintf("ENTRYPOINT [%s]", cmd))
+
+	if target == "" {
+		lines[len(lines)-1] = ""
+		lines = append(lines, injected...)
+	} else {
+		// The entrypoint must land in the target stage, the following stages are not built
+		end, err := stageEnd(lines, target)
+		if err != nil {
+			return "", err
+		}
+		if isEntrypoint(lines[end-1]) {
+			lines[end-1] = ""
+		}
+		lines = slices.Concat(lines[:end], injected, lines[end:])
 	}
-	lines = append(lines, fmt.Sprintf("ENTRYPOINT [%s]", cmd))
 	destPath := fmt.Sprintf("%s.tmp", dockerFilePath)
 	return destPath, os.WriteFile(destPath, []byte(strings.Join(lines, "\n")), 0644)
 }
+
+// stageEnd returns the index of the line following the last instruction of the target stage
+func stageEnd(lines []string, target string) (int, error) {
+	start := -1
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		if start >= 0 {
+			return i, nil
+		}
+		// FROM [--platform=<platform>] <image> AS <name>
+		if n := len(fields); n >= 3 && strings.EqualFold(fields[n-2], "AS") && strings.EqualFold(fields[n-1], target) {
+			start = i
+		}
+	}
+	if start < 0 {
+		return 0, fmt.Errorf("build target %s not found in Dockerfile", target)
+	}
+	return len(lines), nil
+}
+
+func isEntrypoint(line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && (strings.EqualFold(fields[0], "CMD") || strings.EqualFold(fields[0], "ENTRYPOINT"))
+}
@@ -0,0 +1,163 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chdir changes the working directory, where the templates are read from, for the duration of the test
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestCheckTemplate(t *testing.T) {
+	chdir(t, t.TempDir())
+	err := CheckTemplate("Brave")
+	if err == nil || !strings.Contains(err.Error(), "missing Dockerfile template dockerfiles/brave.Dockerfile for repository Brave") {
+		t.Fatalf("err = %v, want the missing template", err)
+	}
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, "brave.Dockerfile"), []byte("FROM node:22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTemplate("Brave"); err != nil {
+		t.Errorf("err = %v with the template", err)
+	}
+}
+
+func TestInjectTarget(t *testing.T) {
+	dockerfile := "FROM node:22 AS build\nRUN npm ci\nFROM node:22-alpine AS runtime\nCOPY --from=build /app /app\nCMD [\"node\"]\nFROM runtime AS dev\nRUN npm i -g nodemon\n"
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{
+			name: "last stage",
+			want: "FROM node:22 AS build\nRUN npm ci\nFROM node:22-alpine AS runtime\nCOPY --from=build /app /app\nCMD [\"node\"]\nFROM runtime AS dev\n\nRUN apk add git\nENTRYPOINT [\"node\",\"dist/index.js\"]",
+		},
+		{
+			name:   "target stage",
+			target: "runtime",
+			want:   "FROM node:22 AS build\nRUN npm ci\nFROM node:22-alpine AS runtime\nCOPY --from=build /app /app\n\nRUN apk add git\nENTRYPOINT [\"node\",\"dist/index.js\"]\nFROM runtime AS dev\nRUN npm i -g nodemon",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+				t.Fatal(err)
+			}
+			path, err := Inject(context.Background(), "brave", dir, "", "Dockerfile", `"node","dist/index.js"`, []string{"apk add git"}, tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", content, tt.want)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Inject(context.Background(), "brave", dir, "", "Dockerfile", `"node"`, nil, "prod")
+	if err == nil || err.Error() != "build target prod not found in Dockerfile" {
+		t.Errorf("err = %v, want the unknown target", err)
+	}
+}
+
+func TestSplitDockerfile(t *testing.T) {
+	tests := map[string][2]string{
+		HubDockerfile:                 {HubDockerfile, "Dockerfile"},
+		"Dockerfile":                  {"/", "Dockerfile"},
+		"/":                           {"/", "Dockerfile"},
+		"src/brave/Dockerfile":        {"src/brave", "Dockerfile"},
+		"docker/server.Dockerfile":    {"docker", "server.Dockerfile"},
+		"src/brave/Dockerfile.alpine": {"src/brave", "Dockerfile.alpine"},
+	}
+	for dockerfile, want := range tests {
+		if dir, file := SplitDockerfile(dockerfile); dir != want[0] || file != want[1] {
+			t.Errorf("SplitDockerfile(%s) = %s, %s, want %s, %s", dockerfile, dir, file, want[0], want[1])
+		}
+	}
+}
+
+func TestInjectFlows(t *testing.T) {
+	chdir(t, t.TempDir())
+	template := "FROM node:22-alpine\nENTRYPOINT [\"node\",\"build/index.js\"]\n"
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, "brave.Dockerfile"), []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		dockerfile string
+		wantPath   string
+		want       string
+	}{
+		{
+			name:       "use template",
+			dockerfile: HubDockerfile,
+			wantPath:   "Dockerfile.tmp",
+			want:       template,
+		},
+		{
+			name:       "use existing",
+			dockerfile: "docker/server.Dockerfile",
+			wantPath:   "docker/server.Dockerfile.tmp",
+			want:       "FROM node:22\nCOPY . .\n\nENTRYPOINT [\"node\",\"dist/index.js\"]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(repo, "docker"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			existing := "FROM node:22\nCOPY . .\nCMD [\"node\",\"index.js\"]\n"
+			if err := os.WriteFile(filepath.Join(repo, "docker", "server.Dockerfile"), []byte(existing), 0644); err != nil {
+				t.Fatal(err)
+			}
+			dir, file := SplitDockerfile(tt.dockerfile)
+			path, err := Inject(context.Background(), "brave", repo, dir, file, `"node","dist/index.js"`, nil, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != filepath.Join(repo, tt.wantPath) {
+				t.Errorf("path = %s, want %s", path, filepath.Join(repo, tt.wantPath))
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", content, tt.want)
+			}
+			if original, _ := os.ReadFile(filepath.Join(repo, "docker", "server.Dockerfile")); string(original) != existing {
+				t.Errorf("repository Dockerfile overwritten:\n%s", original)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// instruction is a Dockerfile instruction with its continuation lines joined
+type instruction struct {
+	line    int
+	command string
+	args    []string
+}
+
+// parseInstructions returns the instructions of a Dockerfile, comments and heredoc bodies are skipped
+func parseInstructions(dockerfile string) []instruction {
+	var instructions []instruction
+	var current strings.Builder
+	start := 0
+	heredoc := ""
+	for i, line := range strings.Split(dockerfile, "\n") {
+		if heredoc != "" {
+			if strings.TrimSpace(line) == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if current.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
+			continue
+		}
+		if current.Len() == 0 {
+			start = i + 1
+		}
+		if continued, ok := strings.CutSuffix(trimmed, "\\"); ok {
+			current.WriteString(continued + " ")
+			continue
+		}
+		current.WriteString(trimmed)
+		fields := strings.Fields(current.String())
+		current.Reset()
+		if len(fields) == 0 {
+			continue
+		}
+		instructions = append(instructions, instruction{line: start, command: strings.ToUpper(fields[0]), args: fields[1:]})
+		for _, field := range fields[1:] {
+			if delimiter, ok := strings.CutPrefix(field, "<<"); ok {
+				heredoc = strings.Trim(strings.TrimPrefix(delimiter, "-"), `"'`)
+				break
+			}
+		}
+	}
+	return instructions
+}
+
+// LintDockerfile statically checks a Dockerfile can be built from the context directory: the sources of its COPY
+// and ADD instructions exist in the context, and the stages they copy from are declared before them.
+// Sources depending on build args, URLs and heredocs are not checked.
+func LintDockerfile(dockerfilePath string, context string) error {
+	content, err := os.ReadFile(dockerfilePath)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	var stages []string
+	for _, inst := range parseInstructions(string(content)) {
+		switch inst.command {
+		case "FROM":
+			// FROM [--platform=<platform>] <image> [AS <name>]
+			args := skipFlags(inst.args)
+			name := ""
+			if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+				name = strings.ToLower(args[2])
+			}
+			stages = append(stages, name)
+		case "COPY", "ADD":
+			if len(stages) == 0 {
+				errs = append(errs, fmt.Errorf("line %d: %s before the first FROM", inst.line, inst.command))
+				continue
+			}
+			if from := flagValue(inst.args, "from"); from != "" {
+				if err := checkStage(from, stages); err != nil {
+					errs = append(errs, fmt.Errorf("line %d: %w", inst.line, err))
+				}
+				continue
+			}
+			for _, source := range copySources(inst.args) {
+				if err := checkSource(context, inst.command, source); err != nil {
+					errs = append(errs, fmt.Errorf("line %d: %w", inst.line, err))
+				}
+			}
+		}
+	}
+	if len(stages) == 0 {
+		errs = append(errs, errors.New("no FROM instruction"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("dockerfile %s: %w", dockerfilePath, errors.Join(errs...))
+	}
+	return nil
+}
+
+// skipFlags returns the arguments of an instruction following its --flags
+func skipFlags(args []string) []string {
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		args = args[1:]
+	}
+	return args
+}
+
+// flagValue returns the value of the --name=value flag of an instruction, empty when not set
+func flagValue(args []string, name string) string {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--"+name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// checkStage fails when the stage of a --from is not declared before, bare names are stages while references
+// with a registry, tag or digest are images
+func checkStage(from string, stages []string) error {
+	if strings.Contains(from, "$") {
+		return nil
+	}
+	if index, err := strconv.Atoi(from); err == nil {
+		// The current stage can't be copied from
+		if index < 0 || index >= len(stages)-1 {
+			return fmt.Errorf("unknown stage %d", index)
+		}
+		return nil
+	}
+	for _, stage := range stages[:len(stages)-1] {
+		if stage == strings.ToLower(from) {
+			return nil
+		}
+	}
+	if strings.ContainsAny(from, "/:@") {
+		return nil
+	}
+	return fmt.Errorf("unknown stage %s", from)
+}
+
+// copySources returns the sources of a COPY or ADD instruction in shell or exec form, the last argument is the
+// destination
+func copySources(args []string) []string {
+	args = skipFlags(args)
+	if joined := strings.Join(args, " "); strings.HasPrefix(joined, "[") {
+		var paths []string
+		if err := json.Unmarshal([]byte(joined), &paths); err == nil {
+			args = paths
+		}
+	}
+	if len(args) < 2 {
+		return nil
+	}
+	return args[:len(args)-1]
+}
+
+// checkSource fails when the source of a COPY or ADD is outside of the context or matches no file of it
+func checkSource(context string, command string, source string) error {
+	if strings.HasPrefix(source, "<<") || strings.Contains(source, "$") {
+		return nil
+	}
+	if command == "ADD" && (strings.Contains(source, "://") || strings.HasPrefix(source, "git@")) {
+		return nil
+	}
+	rel := filepath.Clean(strings.TrimPrefix(source, "/"))
+	if !filepath.IsLocal(rel) && rel != "." {
+		return fmt.Errorf("%s source %s is outside of the build context", command, source)
+	}
+	matches, err := filepath.Glob(filepath.Join(context, rel))
+	if err != nil {
+		return fmt.Errorf("%s source %s: %w", command, source, err)
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("%s source %s not found in the build context %s", command, source, context)
+	}
+	return nil
+}
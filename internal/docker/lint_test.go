@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintDockerfile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"package.json", "src/index.ts", "config/a.json", "config/b.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name       string
+		dockerfile string
+		wantErr    []string
+	}{
+		{
+			name: "clean",
+			dockerfile: `# syntax=docker/dockerfile:1
+FROM node:22-alpine AS builder
+WORKDIR /app
+COPY package.json \
+     src/ ./
+COPY ["config/*.json", "/app/config/"]
+ADD https://example.com/ca.pem /etc/ssl/certs/
+ARG VERSION
+COPY --chown=node:node dist-${VERSION} /app/dist
+RUN <<SCRIPT
+cp -r src /app/build
+COPY missing.js /nowhere
+SCRIPT
+
+FROM node:22-alpine
+COPY --from=builder /app /app
+COPY --from=0 /app/config /config
+COPY --from=ghcr.io/hub/tools:v1 /bin/tool /bin/tool
+`,
+		},
+		{
+			name:       "missing file",
+			dockerfile: "FROM node:22-alpine\nCOPY package.json tsconfig.json ./\n",
+			wantErr:    []string{"line 2: COPY source tsconfig.json not found in the build context " + dir},
+		},
+		{
+			name:       "source outside of the context",
+			dockerfile: "FROM node:22-alpine\nADD ../secrets.env /app/\n",
+			wantErr:    []string{"line 2: ADD source ../secrets.env is outside of the build context"},
+		},
+		{
+			name:       "unknown stages",
+			dockerfile: "FROM node:22-alpine AS builder\nCOPY --from=builder /app /app\nFROM node:22-alpine\nCOPY --from=build /app /app\nCOPY --from=1 /app /app\n",
+			wantErr: []string{
+				"line 2: unknown stage builder",
+				"line 4: unknown stage build",
+				"line 5: unknown stage 1",
+			},
+		},
+		{
+			name:       "no FROM",
+			dockerfile: "COPY package.json ./\n",
+			wantErr:    []string{"line 1: COPY before the first FROM", "no FROM instruction"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerfilePath := filepath.Join(t.TempDir(), "Dockerfile")
+			if err := os.WriteFile(dockerfilePath, []byte(tt.dockerfile), 0644); err != nil {
+				t.Fatal(err)
+			}
+			err := LintDockerfile(dockerfilePath, dir)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("got %v, want a clean Dockerfile", err)
+				}
+				return
+			}
+			want := "dockerfile " + dockerfilePath + ": " + strings.Join(tt.wantErr, "\n")
+			if err == nil || err.Error() != want {
+				t.Errorf("got\n%v\nwant\n%s", err, want)
+			}
+		})
+	}
+}
+
+func TestBuildImageLint(t *testing.T) {
+	dockerLog := fakeTool(t, "docker", "")
+	dir := t.TempDir()
+	dockerfilePath := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(dockerfilePath, []byte("FROM node:22-alpine\nCOPY dist ./dist\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := BuildImage(context.Background(), []string{"registry.test/brave:v1"}, "", "", dockerfilePath, BuildOptions{Lint: true})
+	if err == nil || !strings.Contains(err.Error(), "line 2: COPY source dist not found in the build context") {
+		t.Errorf("err = %v, want the missing source", err)
+	}
+	if calls := toolCalls(t, dockerLog); calls != nil {
+		t.Errorf("docker calls %q, want none before the lint passes", calls)
+	}
+}
@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const (
+	// CatalogArtifactType is the OCI artifact type of the catalog attached to an image
+	CatalogArtifactType = "application/vnd.blaxel.mcp-hub.catalog.v1+json"
+	// SBOMArtifactType is the OCI artifact type of the SPDX SBOM attached to an image
+	SBOMArtifactType = "application/spdx+json"
+	catalogFile      = "catalog.json"
+)
+
+// AttachArgs returns the oras arguments to attach a file to an image, the artifact manifest references the image
+// digest as its subject so the registries list it with the referrers API. plainHTTP reaches an insecure registry.
+func AttachArgs(imageRef string, artifactType string, path string, mediaType string, plainHTTP bool) []string {
+	args := []string{"attach", "--artifact-type", artifactType}
+	if plainHTTP {
+		args = append(args, "--plain-http")
+	}
+	return append(args, imageRef, fmt.Sprintf("%s:%s", path, mediaType))
+}
+
+// CatalogAttachArgs returns the oras arguments to attach a catalog file to an image
+func CatalogAttachArgs(imageRef string, catalogPath string, plainHTTP bool) []string {
+	return AttachArgs(imageRef, CatalogArtifactType, catalogPath, "application/json", plainHTTP)
+}
+
+// AttachCatalog pushes the catalog JSON of an image to its registry as an OCI artifact referencing the image digest,
+// over plain HTTP when plainHTTP is set
+func AttachCatalog(ctx context.Context, imageRef string, catalog []byte, plainHTTP bool) error {
+	if _, err := exec.LookPath("oras"); err != nil {
+		return fmt.Errorf("oras: %w", ErrToolNotFound)
+	}
+
+	dir, err := os.MkdirTemp("", "catalog-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, catalogFile), catalog, 0644); err != nil {
+		return err
+	}
+	if err := attachFile(ctx, CatalogAttachArgs(imageRef, catalogFile, plainHTTP), dir); err != nil {
+		return fmt.Errorf("attach catalog to image %s: %w", imageRef, err)
+	}
+	return nil
+}
+
+// attachFile runs oras attach in the directory of the attached file, oras only accepts files relative to the
+// working directory
+func attachFile(ctx context.Context, args []string, dir string) error {
+	cmd := exec.CommandContext(ctx, "oras", args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
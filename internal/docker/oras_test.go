@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCatalogAttachArgs(t *testing.T) {
+	got := CatalogAttachArgs("ghcr.io/hub/brave@sha256:abc", "catalog.json", false)
+	want := []string{"attach", "--artifact-type", CatalogArtifactType, "ghcr.io/hub/brave@sha256:abc", "catalog.json:application/json"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestAttachArgs(t *testing.T) {
+	const imageRef = "registry.test/hub/brave@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "catalog",
+			args: CatalogAttachArgs(imageRef, "catalog.json", false),
+			want: []string{"attach", "--artifact-type", "application/vnd.blaxel.mcp-hub.catalog.v1+json", imageRef, "catalog.json:application/json"},
+		},
+		{
+			name: "catalog over plain HTTP",
+			args: CatalogAttachArgs(imageRef, "catalog.json", true),
+			want: []string{"attach", "--artifact-type", "application/vnd.blaxel.mcp-hub.catalog.v1+json", "--plain-http", imageRef, "catalog.json:application/json"},
+		},
+		{
+			name: "sbom",
+			args: AttachArgs(imageRef, SBOMArtifactType, "sbom.spdx.json", SBOMArtifactType, false),
+			want: []string{"attach", "--artifact-type", "application/spdx+json", imageRef, "sbom.spdx.json:application/spdx+json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.args, tt.want) {
+				t.Errorf("got  %v\nwant %v", tt.args, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachCatalog(t *testing.T) {
+	attached := filepath.Join(t.TempDir(), "attached.json")
+	orasLog := fakeTool(t, "oras", "cp catalog.json "+attached)
+	catalog := []byte(`{"name":"brave","image":"ghcr.io/hub/brave:v1"}`)
+
+	if err := AttachCatalog(context.Background(), "ghcr.io/hub/brave@sha256:abc", catalog, false); err != nil {
+		t.Fatal(err)
+	}
+	calls := toolCalls(t, orasLog)
+	want := "attach --artifact-type " + CatalogArtifactType + " ghcr.io/hub/brave@sha256:abc catalog.json:application/json"
+	if len(calls) != 1 || calls[0] != want {
+		t.Errorf("oras calls %q, want %q", calls, want)
+	}
+	content, err := os.ReadFile(attached)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(catalog) {
+		t.Errorf("attached %s, want the catalog", content)
+	}
+}
+
+func TestAttachCatalogWithoutOras(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	err := AttachCatalog(context.Background(), "ghcr.io/hub/brave@sha256:abc", []byte("{}"), false)
+	if !errors.Is(err, ErrToolNotFound) {
+		t.Errorf("err = %v, want %v", err, ErrToolNotFound)
+	}
+}
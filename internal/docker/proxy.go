@@ -0,0 +1,21 @@
+package docker
+
+import "os"
+
+var proxyEnv = []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy"}
+
+// ProxyBuildArgs forwards the proxy environment variables to the build, the override replaces HTTP(S)_PROXY
+func ProxyBuildArgs(override string) map[string]string {
+	args := make(map[string]string)
+	for _, key := range proxyEnv {
+		if value := os.Getenv(key); value != "" {
+			args[key] = value
+		}
+	}
+	if override != "" {
+		for _, key := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+			args[key] = override
+		}
+	}
+	return args
+}
@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestProxyBuildArgs(t *testing.T) {
+	for _, key := range proxyEnv {
+		t.Setenv(key, "")
+	}
+	t.Setenv("HTTPS_PROXY", "http://proxy.internal:3128")
+	t.Setenv("NO_PROXY", "localhost")
+
+	tests := []struct {
+		name     string
+		override string
+		want     map[string]string
+	}{
+		{
+			name: "environment forwarded",
+			want: map[string]string{"HTTPS_PROXY": "http://proxy.internal:3128", "NO_PROXY": "localhost"},
+		},
+		{
+			name:     "override replaces the proxies",
+			override: "http://other-proxy:8080",
+			want: map[string]string{
+				"HTTP_PROXY":  "http://other-proxy:8080",
+				"HTTPS_PROXY": "http://other-proxy:8080",
+				"http_proxy":  "http://other-proxy:8080",
+				"https_proxy": "http://other-proxy:8080",
+				"NO_PROXY":    "localhost",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProxyBuildArgs(tt.override); !maps.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
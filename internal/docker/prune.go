@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// BuiltAtLabel is the label holding the build time of the images built by the hub, their creation time is the
+	// date of their commit when they are reproducible
+	BuiltAtLabel = "hub.built-at"
+	// createdAtLayout is the layout of the CreatedAt field of docker image ls
+	createdAtLayout = "2006-01-02 15:04:05 -0700 MST"
+)
+
+// Image is a local image listed by docker image ls
+type Image struct {
+	Repository string
+	Tag        string
+	ID         string
+	// BuiltAt is the time of the BuiltAtLabel, the time the image was last tagged locally without it
+	BuiltAt time.Time
+}
+
+// Ref returns the reference of the image, its ID when it's not tagged
+func (i Image) Ref() string {
+	if i.Tag == "" || i.Tag == "<none>" {
+		return i.ID
+	}
+	return fmt.Sprintf("%s:%s", i.Repository, i.Tag)
+}
+
+// ListImages returns the local images of a repository, e.g. ghcr.io/blaxel-ai/hub/name
+func ListImages(ctx context.Context, repository string) ([]Image, error) {
+	output, err := exec.CommandContext(ctx, "docker", "image", "ls", "--format", "{{.Repository}}\t{{.Tag}}\t{{.ID}}\t{{.CreatedAt}}", repository).Output()
+	if err != nil {
+		return nil, fmt.Errorf("list images of %s: %w", repository, err)
+	}
+	var images []Image
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected docker image ls output: %s", line)
+		}
+		builtAt, err := imageBuiltAt(ctx, fields[2], fields[3])
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, Image{Repository: fields[0], Tag: fields[1], ID: fields[2], BuiltAt: builtAt})
+	}
+	return images, nil
+}
+
+// imageBuiltAt returns the build time label of an image, or the time it was last tagged locally as the images built
+// before the label or reproducible ones have none. The creation time is only used for images never tagged.
+func imageBuiltAt(ctx context.Context, id string, createdAt string) (time.Time, error) {
+	format := fmt.Sprintf("{{index .Config.Labels %q}}\t{{.Metadata.LastTagTime}}", BuiltAtLabel)
+	output, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", format, id).Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("inspect image %s: %w", id, err)
+	}
+	label, lastTagTime, _ := strings.Cut(strings.TrimSpace(string(output)), "\t")
+	if label != "" && label != "<no value>" {
+		builtAt, err := time.Parse(time.RFC3339, label)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse label %s of %s: %w", BuiltAtLabel, id, err)
+		}
+		return builtAt, nil
+	}
+	// Layout of time.Time.String used by the docker templates
+	if tagged, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", lastTagTime); err == nil && !tagged.IsZero() {
+		return tagged, nil
+	}
+	created, err := time.Parse(createdAtLayout, createdAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse creation date of %s: %w", id, err)
+	}
+	return created, nil
+}
+
+// RemoveImage removes a local image
+func RemoveImage(ctx context.Context, ref string) error {
+	if err := exec.CommandContext(ctx, "docker", "image", "rm", ref).Run(); err != nil {
+		return fmt.Errorf("remove image %s: %w", ref, err)
+	}
+	return nil
+}
+
+// PruneDanglingImages removes the untagged images left by the builds
+func PruneDanglingImages(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "docker", "image", "prune", "--force")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// BaseImages returns the external images referenced by the FROM instructions of a Dockerfile.
+// Build stages, scratch and references depending on build args are skipped.
+func BaseImages(dockerfile string) []string {
+	stages := make(map[string]bool)
+	var images []string
+	for _, line := range strings.Split(dockerfile, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		// FROM [--platform=<platform>] <image> [AS <name>]
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+		image := args[0]
+		isStage := stages[strings.ToLower(image)]
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = true
+		}
+		if isStage || image == "scratch" || strings.Contains(image, "$") || slices.Contains(images, image) {
+			continue
+		}
+		images = append(images, image)
+	}
+	return images
+}
+
+// PullBaseImages pulls the base images of the Dockerfile so bad references fail before the build
+func PullBaseImages(ctx context.Context, dockerfilePath string) error {
+	content, err := os.ReadFile(dockerfilePath)
+	if err != nil {
+		return err
+	}
+	for _, image := range BaseImages(string(content)) {
+		fmt.Println("Pulling base image", image)
+		cmd := exec.CommandContext(ctx, "docker", "pull", image)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pull base image %s: %w", image, err)
+		}
+	}
+	return nil
+}
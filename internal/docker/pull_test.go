@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBaseImages(t *testing.T) {
+	dockerfile := `ARG NODE_VERSION=22
+FROM --platform=linux/amd64 node:22-alpine AS builder
+RUN npm ci
+FROM builder AS test
+from python:3.12-slim
+FROM node:${NODE_VERSION}
+FROM scratch
+FROM node:22-alpine
+COPY --from=builder /app /app
+`
+	want := []string{"node:22-alpine", "python:3.12-slim"}
+	if got := BaseImages(dockerfile); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBuildImagePullFailure(t *testing.T) {
+	dockerLog := fakeTool(t, "docker", `if [ "$1" = pull ] && [ "$2" = "node:99-missing" ]; then exit 1; fi`)
+	tests := []struct {
+		name      string
+		from      string
+		wantErr   bool
+		wantBuild bool
+	}{
+		{name: "bad reference aborts the build", from: "node:99-missing", wantErr: true},
+		{name: "pulled base image", from: "node:22-alpine", wantBuild: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(dockerLog)
+			dir := t.TempDir()
+			dockerfilePath := filepath.Join(dir, "Dockerfile")
+			if err := os.WriteFile(dockerfilePath, []byte("FROM "+tt.from+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := BuildImage(context.Background(), []string{"registry.test/brave:v1"}, "", "", dockerfilePath, BuildOptions{Pull: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			calls := toolCalls(t, dockerLog)
+			if len(calls) == 0 || calls[0] != "pull "+tt.from {
+				t.Errorf("calls = %v, want the base image pulled first", calls)
+			}
+			built := slices.ContainsFunc(calls, func(call string) bool { return strings.HasPrefix(call, "build ") })
+			if built != tt.wantBuild {
+				t.Errorf("calls = %v, want build %v", calls, tt.wantBuild)
+			}
+		})
+	}
+}
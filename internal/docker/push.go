@@ -1,9 +1,14 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
+	"slices"
+	"strings"
 )
 
 func PushImage(ctx context.Context, imageName string) error {
@@ -16,3 +21,87 @@ func PushImage(ctx context.Context, imageName string) error {
 	}
 	return nil
 }
+
+// remoteManifest is the verbose output of docker manifest inspect for an image of a single platform
+type remoteManifest struct {
+	Descriptor struct {
+		Digest string `json:"digest"`
+	} `json:"Descriptor"`
+	SchemaV2Manifest *struct {
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	} `json:"SchemaV2Manifest"`
+	OCIManifest *struct {
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	} `json:"OCIManifest"`
+}
+
+// remoteDigests returns the manifest and config digests of the verbose docker manifest inspect output,
+// nil for a multi-platform image as it can't be compared to a local image
+func remoteDigests(output []byte) ([]string, error) {
+	if trimmed := bytes.TrimSpace(output); len(trimmed) > 0 && trimmed[0] == '[' {
+		return nil, nil
+	}
+	var manifest remoteManifest
+	if err := json.Unmarshal(output, &manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	digests := []string{manifest.Descriptor.Digest}
+	if manifest.SchemaV2Manifest != nil {
+		digests = append(digests, manifest.SchemaV2Manifest.Config.Digest)
+	}
+	if manifest.OCIManifest != nil {
+		digests = append(digests, manifest.OCIManifest.Config.Digest)
+	}
+	return digests, nil
+}
+
+// ImagePushed returns the digest reference of the image in the registry, e.g. ghcr.io/hub/name@sha256:..., when its
+// tag already points to the local image, its id is the config digest, or the manifest digest with the containerd
+// image store. It returns an empty reference when the registry doesn't have the image. insecure reaches the registry
+// over plain HTTP.
+func ImagePushed(ctx context.Context, imageName string, insecure bool) (string, error) {
+	id, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{.Id}}", imageName).Output()
+	if err != nil {
+		return "", fmt.Errorf("inspect image %s: %w", imageName, err)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", ManifestInspectArgs(imageName, insecure)...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(strings.ToLower(stderr.String()), "no such manifest") {
+			return "", nil
+		}
+		return "", fmt.Errorf("inspect manifest %s: %s", imageName, strings.TrimSpace(stderr.String()))
+	}
+	digests, err := remoteDigests(output)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(digests, strings.TrimSpace(string(id))) {
+		return "", nil
+	}
+	return digestRef(imageName, digests[0]), nil
+}
+
+// ManifestInspectArgs returns the docker arguments to read the manifest of an image from its registry
+func ManifestInspectArgs(imageName string, insecure bool) []string {
+	args := []string{"manifest", "inspect", "--verbose"}
+	if insecure {
+		args = append(args, "--insecure")
+	}
+	return append(args, imageName)
+}
+
+// digestRef returns the reference of the digest in the repository of the image: registry/name:tag -> registry/name@digest
+func digestRef(imageName string, digest string) string {
+	repository := imageName
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	return repository + "@" + digest
+}
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRemoteDigests(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "docker manifest",
+			output: `{"Descriptor":{"digest":"sha256:manifest"},"SchemaV2Manifest":{"config":{"digest":"sha256:config"}}}`,
+			want:   []string{"sha256:manifest", "sha256:config"},
+		},
+		{
+			name:   "oci manifest",
+			output: `{"Descriptor":{"digest":"sha256:manifest"},"OCIManifest":{"config":{"digest":"sha256:config"}}}`,
+			want:   []string{"sha256:manifest", "sha256:config"},
+		},
+		{
+			name:   "multi-platform image",
+			output: `[{"Descriptor":{"digest":"sha256:amd64"}}]`,
+		},
+		{
+			name:    "invalid output",
+			output:  `not json`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := remoteDigests([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("digests = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDigestRef(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "ghcr.io/hub/brave:v1", want: "ghcr.io/hub/brave@sha256:abc"},
+		{image: "localhost:5000/hub/brave:v1", want: "localhost:5000/hub/brave@sha256:abc"},
+		{image: "localhost:5000/hub/brave", want: "localhost:5000/hub/brave@sha256:abc"},
+	}
+	for _, tt := range tests {
+		if got := digestRef(tt.image, "sha256:abc"); got != tt.want {
+			t.Errorf("digestRef(%s) = %s, want %s", tt.image, got, tt.want)
+		}
+	}
+}
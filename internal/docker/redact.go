@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces the secret values of a logged command
+const Redacted = "***"
+
+// secretName matches the names of environment variables and build args that usually hold a secret
+var secretName = regexp.MustCompile(`(?i)(secret|token|password|passwd|api_?key|private_?key|credential|auth)`)
+
+// valueFlags are the docker flags followed by a KEY=value argument
+var valueFlags = []string{"-e", "--env", "--build-arg"}
+
+// RedactArgs returns a copy of the arguments of a docker command safe to log, the values of the env and build args
+// named in secretKeys or with a secret-like name are masked, and so are the passwords of the URLs
+func RedactArgs(args []string, secretKeys []string) []string {
+	redacted := slices.Clone(args)
+	for i, arg := range redacted {
+		flag, value, inline := strings.Cut(arg, "=")
+		if inline && slices.Contains(valueFlags, flag) {
+			// --build-arg=KEY=value
+			redacted[i] = flag + "=" + redactAssignment(value, secretKeys)
+		} else if i > 0 && slices.Contains(valueFlags, args[i-1]) {
+			redacted[i] = redactAssignment(arg, secretKeys)
+		}
+	}
+	return redacted
+}
+
+// redactAssignment masks the value of a KEY=value argument when the key is a secret
+func redactAssignment(assignment string, secretKeys []string) string {
+	key, value, ok := strings.Cut(assignment, "=")
+	if !ok || value == "" {
+		return assignment
+	}
+	if slices.Contains(secretKeys, key) || secretName.MatchString(key) {
+		return key + "=" + Redacted
+	}
+	// Proxy URLs carry their credentials
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return key + "=" + strings.Replace(u.Redacted(), ":xxxxx@", ":"+Redacted+"@", 1)
+		}
+	}
+	return assignment
+}
@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+)
+
+type registryConfig struct {
+	InsecureRegistryCIDRs []string `json:"InsecureRegistryCIDRs"`
+	IndexConfigs          map[string]struct {
+		Secure bool `json:"Secure"`
+	} `json:"IndexConfigs"`
+}
+
+// RegistryHost returns the host of a registry reference: localhost:5000/hub -> localhost:5000
+func RegistryHost(registry string) string {
+	host, _, _ := strings.Cut(registry, "/")
+	return host
+}
+
+// CheckInsecureRegistry verifies the docker daemon allows plain HTTP for the registry.
+// The docker CLI can't disable TLS per push, the registry must be in the daemon insecure-registries.
+func CheckInsecureRegistry(ctx context.Context, registry string) error {
+	output, err := exec.CommandContext(ctx, "docker", "info", "--format", "{{json .RegistryConfig}}").Output()
+	if err != nil {
+		return fmt.Errorf("failed to read docker daemon registry config: %w", err)
+	}
+	var config registryConfig
+	if err := json.Unmarshal(output, &config); err != nil {
+		return fmt.Errorf("failed to decode docker daemon registry config: %w", err)
+	}
+	return checkInsecureRegistry(config, RegistryHost(registry))
+}
+
+func checkInsecureRegistry(config registryConfig, host string) error {
+	if index, ok := config.IndexConfigs[host]; ok && !index.Secure {
+		return nil
+	}
+
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	ips, _ := net.LookupIP(hostname)
+	for _, cidr := range config.InsecureRegistryCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		for _, ip := range ips {
+			if network.Contains(ip) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf(`registry %s is not an insecure registry of the docker daemon, add it to /etc/docker/daemon.json and restart docker:
+  {
+    "insecure-registries": ["%s"]
+  }`, host, host)
+}
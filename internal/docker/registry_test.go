@@ -0,0 +1,58 @@
+package docker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckInsecureRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "insecure index", config: `{"IndexConfigs": {"localhost:5000": {"Secure": false}}}`},
+		{name: "insecure cidr", config: `{"InsecureRegistryCIDRs": ["127.0.0.0/8"], "IndexConfigs": {}}`},
+		{name: "secure index", config: `{"IndexConfigs": {"localhost:5000": {"Secure": true}}}`, wantErr: true},
+		{name: "unknown registry", config: `{"IndexConfigs": {"docker.io": {"Secure": true}}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeTool(t, "docker", "echo '"+tt.config+"'")
+			err := CheckInsecureRegistry(context.Background(), "localhost:5000/hub")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), `"insecure-registries": ["localhost:5000"]`) {
+				t.Errorf("err = %v, want the daemon.json remediation", err)
+			}
+		})
+	}
+}
+
+func TestInsecureRegistryArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		insecure string
+	}{
+		{name: "manifest inspect", args: ManifestInspectArgs("localhost:5000/hub/brave:v1", true), insecure: "--insecure"},
+		{name: "catalog attach", args: CatalogAttachArgs("localhost:5000/hub/brave@sha256:abc", "catalog.json", true), insecure: "--plain-http"},
+		{name: "sbom attach", args: AttachArgs("localhost:5000/hub/brave@sha256:abc", SBOMArtifactType, "sbom.json", SBOMArtifactType, true), insecure: "--plain-http"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Contains(tt.args, tt.insecure) {
+				t.Errorf("args %v have no %s", tt.args, tt.insecure)
+			}
+		})
+	}
+	if args := ManifestInspectArgs("ghcr.io/hub/brave:v1", false); slices.Contains(args, "--insecure") {
+		t.Errorf("args %v of a secure registry have --insecure", args)
+	}
+	if args := CatalogAttachArgs("ghcr.io/hub/brave@sha256:abc", "catalog.json", false); slices.Contains(args, "--plain-http") {
+		t.Errorf("args %v of a secure registry have --plain-http", args)
+	}
+}
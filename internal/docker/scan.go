@@ -0,0 +1,50 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// minSecretLength is the length under which a value is too common to be reported as a leaked secret
+const minSecretLength = 6
+
+// ScanSecrets returns the names of the secrets whose value is in the env of the image config
+// or in the commands of its layers, e.g. through a build arg
+func ScanSecrets(ctx context.Context, imageName string, secrets map[string]string) ([]string, error) {
+	output, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{json .Config.Env}}", imageName).Output()
+	if err != nil {
+		return nil, fmt.Errorf("inspect image %s: %w", imageName, err)
+	}
+	var env []string
+	if err := json.Unmarshal(output, &env); err != nil {
+		return nil, fmt.Errorf("parse env of image %s: %w", imageName, err)
+	}
+	history, err := exec.CommandContext(ctx, "docker", "image", "history", "--no-trunc", "--format", "{{.CreatedBy}}", imageName).Output()
+	if err != nil {
+		return nil, fmt.Errorf("read history of image %s: %w", imageName, err)
+	}
+	return FindSecrets(append(env, strings.Split(string(history), "\n")...), secrets), nil
+}
+
+// FindSecrets returns the sorted names of the secrets whose value is in one of the texts
+func FindSecrets(texts []string, secrets map[string]string) []string {
+	var found []string
+	for _, name := range slices.Sorted(maps.Keys(secrets)) {
+		value := secrets[name]
+		if len(value) < minSecretLength {
+			continue
+		}
+		for _, text := range texts {
+			if strings.Contains(text, value) {
+				found = append(found, name)
+				break
+			}
+		}
+	}
+	return found
+}
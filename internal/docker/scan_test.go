@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScanSecrets(t *testing.T) {
+	secrets := map[string]string{"BRAVE_API_KEY": "sk-brave-123456", "GITHUB_TOKEN": "ghp_abcdef123456", "REGION": "eu"}
+	tests := []struct {
+		name    string
+		env     string
+		history string
+		want    []string
+	}{
+		{
+			name:    "secret in the env",
+			env:     `["PATH=/usr/bin","BRAVE_API_KEY=sk-brave-123456"]`,
+			history: "CMD [\"node\"]",
+			want:    []string{"BRAVE_API_KEY"},
+		},
+		{
+			name:    "secret in a layer command",
+			env:     `["PATH=/usr/bin"]`,
+			history: "RUN |1 TOKEN=ghp_abcdef123456 /bin/sh -c npm ci\nCMD [\"node\"]",
+			want:    []string{"GITHUB_TOKEN"},
+		},
+		{
+			name:    "short value ignored",
+			env:     `["PATH=/usr/bin","REGION=eu"]`,
+			history: "CMD [\"node\"]",
+		},
+		{
+			name:    "clean image",
+			env:     `["PATH=/usr/bin"]`,
+			history: "CMD [\"node\"]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeTool(t, "docker", `case "$2" in
+inspect) echo '`+tt.env+`' ;;
+history) printf '%s\n' '`+tt.history+`' ;;
+esac`)
+			got, err := ScanSecrets(context.Background(), "registry.test/brave:v1", secrets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanSecretsMissingImage(t *testing.T) {
+	fakeTool(t, "docker", "exit 1")
+	_, err := ScanSecrets(context.Background(), "registry.test/brave:v1", map[string]string{"BRAVE_API_KEY": "sk-brave-123456"})
+	if err == nil || !strings.HasPrefix(err.Error(), "inspect image registry.test/brave:v1: ") {
+		t.Errorf("err = %v, want the failed inspect", err)
+	}
+}
@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrToolNotFound is returned when an optional supply chain tool is not installed
+var ErrToolNotFound = errors.New("tool not found")
+
+// ImageDigest returns the repository digest reference of a pushed image, e.g. ghcr.io/hub/name@sha256:...
+func ImageDigest(ctx context.Context, imageName string) (string, error) {
+	output, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{json .RepoDigests}}", imageName).Output()
+	if err != nil {
+		return "", fmt.Errorf("inspect image %s: %w", imageName, err)
+	}
+	repository := imageName
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	for _, digest := range strings.Split(strings.Trim(strings.TrimSpace(string(output)), "[]"), ",") {
+		digest = strings.Trim(digest, `"`)
+		if strings.HasPrefix(digest, repository+"@") {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("no digest found for image %s, it must be pushed first", imageName)
+}
+
+// SignArgs returns the cosign arguments to sign an image, key-based when key is set, keyless otherwise
+func SignArgs(imageRef string, key string) []string {
+	args := []string{"sign", "--yes"}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	return append(args, imageRef)
+}
+
+// SBOMArgs returns the cosign arguments to attach a SPDX SBOM to an image as an attestation
+func SBOMArgs(imageRef string, sbomPath string, key string) []string {
+	args := []string{"attest", "--yes", "--type", "spdxjson", "--predicate", sbomPath}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	return append(args, imageRef)
+}
+
+// SignImage signs the image with cosign and returns the reference of its signature.
+// The key is read from COSIGN_KEY, keyless signing is used when it's not set.
+func SignImage(ctx context.Context, imageRef string) (string, error) {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return "", fmt.Errorf("cosign: %w", ErrToolNotFound)
+	}
+	if err := runTool(ctx, "cosign", SignArgs(imageRef, os.Getenv("COSIGN_KEY"))...); err != nil {
+		return "", fmt.Errorf("sign image %s: %w", imageRef, err)
+	}
+	output, err := exec.CommandContext(ctx, "cosign", "triangulate", imageRef).Output()
+	if err != nil {
+		return "", fmt.Errorf("find signature of image %s: %w", imageRef, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// AttachSBOM generates the SBOM of the image with syft and attaches it with cosign, and as an OCI referrer of the
+// image with oras when referrer is set, over plain HTTP when plainHTTP is set
+func AttachSBOM(ctx context.Context, imageRef string, referrer bool, plainHTTP bool) error {
+	tools := []string{"syft", "cosign"}
+	if referrer {
+		tools = append(tools, "oras")
+	}
+	for _, tool := range tools {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s: %w", tool, ErrToolNotFound)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "sbom-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	sbomFile := "sbom.spdx.json"
+	sbomPath := filepath.Join(dir, sbomFile)
+
+	if err := runTool(ctx, "syft", imageRef, "-o", fmt.Sprintf("spdx-json=%s", sbomPath)); err != nil {
+		return fmt.Errorf("generate sbom of image %s: %w", imageRef, err)
+	}
+	if err := runTool(ctx, "cosign", SBOMArgs(imageRef, sbomPath, os.Getenv("COSIGN_KEY"))...); err != nil {
+		return fmt.Errorf("attach sbom to image %s: %w", imageRef, err)
+	}
+	if referrer {
+		if err := attachFile(ctx, AttachArgs(imageRef, SBOMArtifactType, sbomFile, SBOMArtifactType, plainHTTP), dir); err != nil {
+			return fmt.Errorf("attach sbom referrer to image %s: %w", imageRef, err)
+		}
+	}
+	return nil
+}
+
+func runTool(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
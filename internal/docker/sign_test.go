@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSignArgs(t *testing.T) {
+	imageRef := "ghcr.io/hub/brave@sha256:abc"
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "keyless sign", args: SignArgs(imageRef, ""), want: []string{"sign", "--yes", imageRef}},
+		{name: "key sign", args: SignArgs(imageRef, "cosign.key"), want: []string{"sign", "--yes", "--key", "cosign.key", imageRef}},
+		{
+			name: "keyless sbom",
+			args: SBOMArgs(imageRef, "sbom.json", ""),
+			want: []string{"attest", "--yes", "--type", "spdxjson", "--predicate", "sbom.json", imageRef},
+		},
+		{
+			name: "key sbom",
+			args: SBOMArgs(imageRef, "sbom.json", "cosign.key"),
+			want: []string{"attest", "--yes", "--type", "spdxjson", "--predicate", "sbom.json", "--key", "cosign.key", imageRef},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.args, tt.want) {
+				t.Errorf("got %v, want %v", tt.args, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignImage(t *testing.T) {
+	t.Setenv("COSIGN_KEY", "cosign.key")
+	cosignLog := fakeTool(t, "cosign", `if [ "$1" = triangulate ]; then echo ghcr.io/hub/brave:sha256-abc.sig; fi`)
+
+	signature, err := SignImage(context.Background(), "ghcr.io/hub/brave@sha256:abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature != "ghcr.io/hub/brave:sha256-abc.sig" {
+		t.Errorf("signature = %s", signature)
+	}
+	want := []string{"sign --yes --key cosign.key ghcr.io/hub/brave@sha256:abc", "triangulate ghcr.io/hub/brave@sha256:abc"}
+	if calls := toolCalls(t, cosignLog); !slices.Equal(calls, want) {
+		t.Errorf("cosign calls = %v, want %v", calls, want)
+	}
+}
+
+func TestSignImageWithoutCosign(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := SignImage(context.Background(), "ghcr.io/hub/brave@sha256:abc"); !errors.Is(err, ErrToolNotFound) {
+		t.Errorf("err = %v, want ErrToolNotFound", err)
+	}
+}
+
+func TestImageDigest(t *testing.T) {
+	fakeTool(t, "docker", `echo '["docker.io/other/brave@sha256:other","ghcr.io/hub/brave@sha256:abc"]'`)
+	digest, err := ImageDigest(context.Background(), "ghcr.io/hub/brave:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != "ghcr.io/hub/brave@sha256:abc" {
+		t.Errorf("digest = %s", digest)
+	}
+	if _, err := ImageDigest(context.Background(), "ghcr.io/hub/notion:v1"); err == nil {
+		t.Error("expected an error for an image that was not pushed")
+	}
+}
+
+func TestAttachSBOM(t *testing.T) {
+	const imageRef = "registry.test/hub/brave@sha256:abc"
+	tests := []struct {
+		name      string
+		referrer  bool
+		plainHTTP bool
+		wantOras  []string
+	}{
+		{name: "attestation only"},
+		{
+			name:     "referrer",
+			referrer: true,
+			wantOras: []string{"attach --artifact-type application/spdx+json " + imageRef + " sbom.spdx.json:application/spdx+json"},
+		},
+		{
+			name:      "referrer over plain HTTP",
+			referrer:  true,
+			plainHTTP: true,
+			wantOras:  []string{"attach --artifact-type application/spdx+json --plain-http " + imageRef + " sbom.spdx.json:application/spdx+json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attached := filepath.Join(t.TempDir(), "attached.json")
+			fakeTool(t, "syft", `echo '{"spdxVersion":"SPDX-2.3"}' > "${3#spdx-json=}"`)
+			cosignLog := fakeTool(t, "cosign", "")
+			orasLog := fakeTool(t, "oras", "cp sbom.spdx.json "+attached)
+
+			if err := AttachSBOM(context.Background(), imageRef, tt.referrer, tt.plainHTTP); err != nil {
+				t.Fatal(err)
+			}
+			if calls := toolCalls(t, cosignLog); len(calls) != 1 || !strings.HasPrefix(calls[0], "attest --yes --type spdxjson --predicate ") {
+				t.Errorf("cosign calls %q, want the attestation", calls)
+			}
+			if calls := toolCalls(t, orasLog); !slices.Equal(calls, tt.wantOras) {
+				t.Errorf("oras calls %q, want %q", calls, tt.wantOras)
+			}
+			if !tt.referrer {
+				return
+			}
+			content, err := os.ReadFile(attached)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(string(content)) != `{"spdxVersion":"SPDX-2.3"}` {
+				t.Errorf("attached %s, want the SBOM generated by syft", content)
+			}
+		})
+	}
+}
+
+func TestAttachSBOMWithoutOras(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	fakeTool(t, "syft", "")
+	cosignLog := fakeTool(t, "cosign", "")
+	if err := AttachSBOM(context.Background(), "registry.test/hub/brave@sha256:abc", true, false); !errors.Is(err, ErrToolNotFound) || err.Error() != "oras: tool not found" {
+		t.Errorf("err = %v, want oras not found", err)
+	}
+	if calls := toolCalls(t, cosignLog); calls != nil {
+		t.Errorf("cosign calls %q, want none without oras", calls)
+	}
+}
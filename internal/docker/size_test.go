@@ -0,0 +1,44 @@
+package docker
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckImageSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		docker  string
+		opts    BuildOptions
+		wantErr string
+		calls   int
+	}{
+		{name: "below the maximum", docker: "echo 104857600", opts: BuildOptions{MaxSize: 200 << 20}, calls: 1},
+		{name: "at the maximum", docker: "echo 209715200", opts: BuildOptions{MaxSize: 200 << 20}, calls: 1},
+		{
+			name:    "above the maximum",
+			docker:  "echo 2147483648",
+			opts:    BuildOptions{MaxSize: 200 << 20},
+			wantErr: "image ghcr.io/hub/brave:v1 is 2048.0MiB, larger than the maximum of 200.0MiB",
+			calls:   1,
+		},
+		{name: "multi-platform image", docker: "echo 2147483648", opts: BuildOptions{MaxSize: 200 << 20, Platforms: []string{"linux/amd64", "linux/arm64"}}},
+		{name: "missing image", docker: "exit 1", opts: BuildOptions{MaxSize: 200 << 20}, wantErr: "inspect image ghcr.io/hub/brave:v1: exit status 1", calls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeTool(t, "docker", tt.docker)
+			err := checkImageSize(context.Background(), "ghcr.io/hub/brave:v1", tt.opts)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("err = %q, want %q", got, tt.wantErr)
+			}
+			if calls := toolCalls(t, logPath); len(calls) != tt.calls {
+				t.Errorf("docker calls %q, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
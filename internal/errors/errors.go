@@ -0,0 +1,61 @@
+// Package errors defines the errors of the import stages of a repository, callers branch on them with errors.As
+package errors
+
+import "fmt"
+
+// CloneError is returned when the source of a repository can't be fetched
+type CloneError struct {
+	Repository string
+	Err        error
+}
+
+func (e *CloneError) Error() string {
+	return fmt.Sprintf("clone repository: %v", e.Err)
+}
+
+func (e *CloneError) Unwrap() error {
+	return e.Err
+}
+
+// BuildError is returned when the image of a repository can't be built, including its build hooks
+type BuildError struct {
+	Repository string
+	Err        error
+}
+
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("build image: %v", e.Err)
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// PushError is returned when a built image can't be pushed to the registry, it can be retried
+type PushError struct {
+	Repository string
+	Image      string
+	Err        error
+}
+
+func (e *PushError) Error() string {
+	return fmt.Sprintf("push image %s: %v", e.Image, e.Err)
+}
+
+func (e *PushError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned when the config of a repository is invalid
+type ValidationError struct {
+	Repository string
+	Err        error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("repository %s: %v", e.Repository, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestErrorsAs(t *testing.T) {
+	cause := fs.ErrNotExist
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "clone", err: &CloneError{Repository: "brave", Err: cause}, want: "clone repository: file does not exist"},
+		{name: "build", err: &BuildError{Repository: "brave", Err: cause}, want: "build image: file does not exist"},
+		{name: "push", err: &PushError{Repository: "brave", Image: "ghcr.io/hub/brave:v1", Err: cause}, want: "push image ghcr.io/hub/brave:v1: file does not exist"},
+		{name: "validation", err: &ValidationError{Repository: "brave", Err: cause}, want: "repository brave: file does not exist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			// Wrapped by a caller, the error is still classified and its cause reachable
+			err := fmt.Errorf("process repository: %w", tt.err)
+			if !stderrors.Is(err, cause) {
+				t.Errorf("%v doesn't wrap its cause", err)
+			}
+			var cloneErr *CloneError
+			var buildErr *BuildError
+			var pushErr *PushError
+			var validationErr *ValidationError
+			got := map[string]bool{
+				"clone":      stderrors.As(err, &cloneErr),
+				"build":      stderrors.As(err, &buildErr),
+				"push":       stderrors.As(err, &pushErr),
+				"validation": stderrors.As(err, &validationErr),
+			}
+			for kind, matched := range got {
+				if matched != (kind == tt.name) {
+					t.Errorf("errors.As %s = %t", kind, matched)
+				}
+			}
+		})
+	}
+}
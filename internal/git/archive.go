@@ -0,0 +1,129 @@
+package git
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// codeloadURL serves the tarballs of the GitHub repositories
+var codeloadURL = "https://codeload.github.com"
+
+// ArchiveURL returns the URL of the tarball of a ref of the repository, false when its host serves none
+func ArchiveURL(repositoryURL string, ref string) (string, bool) {
+	u, err := url.Parse(repositoryURL)
+	if err != nil || u.Host != "github.com" {
+		return "", false
+	}
+	owner, repo, ok := strings.Cut(strings.Trim(strings.TrimSuffix(u.Path, ".git"), "/"), "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", false
+	}
+	return fmt.Sprintf("%s/%s/%s/tar.gz/%s", codeloadURL, owner, repo, url.PathEscape(ref)), true
+}
+
+// DownloadArchive downloads the tarball of the commit, or the branch when empty, and extracts it to path.
+// The checkout has no git history, it is only suitable for building.
+func DownloadArchive(path string, branch string, commit string, repositoryURL string, proxy string) error {
+	ref := commit
+	if ref == "" {
+		ref = branch
+	}
+	archiveURL, ok := ArchiveURL(repositoryURL, ref)
+	if !ok {
+		return fmt.Errorf("no archive available for %s", repositoryURL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	client := &http.Client{Transport: transport, Timeout: 10 * time.Minute}
+	resp, err := client.Get(archiveURL)
+	if err != nil {
+		return fmt.Errorf("download archive %s: %w", archiveURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download archive %s: HTTP %d", archiveURL, resp.StatusCode)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read archive %s: %w", archiveURL, err)
+	}
+	defer gz.Close()
+	return extractSource(gz, path)
+}
+
+// extractSource extracts a tarball to dest, stripping its top level directory and keeping the file modes
+// and the symlinks staying inside of dest
+func extractSource(r io.Reader, dest string) error {
+	dest = filepath.Clean(dest)
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, name, _ := strings.Cut(header.Name, "/")
+		if name == "" {
+			continue
+		}
+		target := filepath.Join(dest, name)
+		if !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			resolved := filepath.Join(filepath.Dir(target), header.Linkname)
+			if filepath.IsAbs(header.Linkname) || !strings.HasPrefix(resolved, dest+string(os.PathSeparator)) {
+				return fmt.Errorf("invalid symlink in archive: %s -> %s", header.Name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, tr); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
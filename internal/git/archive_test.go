@@ -0,0 +1,137 @@
+package git
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// archiveEntry is a file, a directory or a symlink of a test tarball
+type archiveEntry struct {
+	name     string
+	typeflag byte
+	content  string
+	linkname string
+	mode     int64
+}
+
+// tarball returns the gzipped tarball of the entries
+func tarball(t *testing.T, entries ...archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Typeflag: entry.typeflag, Linkname: entry.linkname, Mode: entry.mode, Size: int64(len(entry.content))}
+		if header.Mode == 0 {
+			header.Mode = 0644
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{url: "https://github.com/hub/brave.git", want: "https://codeload.github.com/hub/brave/tar.gz/v1.0.0", wantOK: true},
+		{url: "https://github.com/hub/brave/", want: "https://codeload.github.com/hub/brave/tar.gz/v1.0.0", wantOK: true},
+		{url: "https://gitlab.com/hub/brave.git"},
+		{url: "https://github.com/hub"},
+		{url: "https://github.com/hub/brave/tree/main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, ok := ArchiveURL(tt.url, "v1.0.0")
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDownloadArchive(t *testing.T) {
+	archives := map[string][]byte{
+		"/hub/brave/tar.gz/abc123": tarball(t,
+			archiveEntry{name: "brave-abc123/", typeflag: tar.TypeDir, mode: 0755},
+			archiveEntry{name: "brave-abc123/Dockerfile", typeflag: tar.TypeReg, content: "FROM node:22-alpine\n"},
+			archiveEntry{name: "brave-abc123/bin/start.sh", typeflag: tar.TypeReg, content: "#!/bin/sh\n", mode: 0755},
+			archiveEntry{name: "brave-abc123/bin/start", typeflag: tar.TypeSymlink, linkname: "start.sh"},
+		),
+		"/hub/escape/tar.gz/main": tarball(t,
+			archiveEntry{name: "escape-main/../../evil", typeflag: tar.TypeReg, content: "evil"},
+		),
+		"/hub/link/tar.gz/main": tarball(t,
+			archiveEntry{name: "link-main/passwd", typeflag: tar.TypeSymlink, linkname: "../../etc/passwd"},
+		),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archive, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(archive)
+	}))
+	defer server.Close()
+	prev := codeloadURL
+	codeloadURL = server.URL
+	defer func() { codeloadURL = prev }()
+
+	t.Run("extracted to the path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "brave", "main")
+		if err := DownloadArchive(path, "main", "abc123", "https://github.com/hub/brave.git", ""); err != nil {
+			t.Fatal(err)
+		}
+		content, err := os.ReadFile(filepath.Join(path, "Dockerfile"))
+		if err != nil || string(content) != "FROM node:22-alpine\n" {
+			t.Errorf("Dockerfile = %q, %v", content, err)
+		}
+		if info, err := os.Stat(filepath.Join(path, "bin", "start.sh")); err != nil || info.Mode().Perm() != 0755 {
+			t.Errorf("start.sh = %v, %v, want an executable", info, err)
+		}
+		if link, err := os.Readlink(filepath.Join(path, "bin", "start")); err != nil || link != "start.sh" {
+			t.Errorf("start links to %q, %v", link, err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		branch  string
+		url     string
+		wantErr string
+	}{
+		{name: "no archive for the host", branch: "main", url: "https://gitlab.com/hub/brave.git", wantErr: "no archive available for https://gitlab.com/hub/brave.git"},
+		{name: "unknown ref", branch: "develop", url: "https://github.com/hub/brave.git", wantErr: "/hub/brave/tar.gz/develop: HTTP 404"},
+		{name: "path escaping the destination", branch: "main", url: "https://github.com/hub/escape.git", wantErr: "invalid path in archive: escape-main/../../evil"},
+		{name: "symlink escaping the destination", branch: "main", url: "https://github.com/hub/link.git", wantErr: "invalid symlink in archive: link-main/passwd -> ../../etc/passwd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DownloadArchive(filepath.Join(t.TempDir(), "src"), tt.branch, "", tt.url, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,21 +1,96 @@
 package git
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 )
 
-func CloneRepository(path string, branch string, url string) (*git.Repository, error) {
-	return git.PlainClone(path, false, &git.CloneOptions{
+// CloneRepository clones the branch of the repository, and checks out the commit when not empty
+func CloneRepository(path string, branch string, commit string, url string, proxy string) (*git.Repository, error) {
+	proxyOptions, err := ProxyOptions(proxy, url)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := git.PlainClone(path, false, &git.CloneOptions{
 		URL:           url,
 		ReferenceName: plumbing.NewBranchReferenceName(branch),
 		SingleBranch:  true,
-		Progress:      os.Stdout,
+		Progress:      os.Stderr,
+		ProxyOptions:  proxyOptions,
 	})
+	if err != nil || commit == "" {
+		return repo, err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		return nil, fmt.Errorf("commit %s not found on branch %s: %w", commit, branch, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return nil, fmt.Errorf("checkout commit %s: %w", commit, err)
+	}
+	return repo, nil
+}
+
+// CommitEpoch returns the author date of the checked out commit of the repository at path as a unix timestamp,
+// the SOURCE_DATE_EPOCH of reproducible builds
+func CommitEpoch(path string) (int64, error) {
+	repo, head, err := openHead(path)
+	if err != nil {
+		return 0, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return 0, err
+	}
+	return commit.Author.When.Unix(), nil
+}
+
+// HeadCommit returns the SHA of the checked out commit of the repository at path
+func HeadCommit(path string) (string, error) {
+	_, head, err := openHead(path)
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+// openHead opens the repository at path, never one of its parents, and returns its HEAD
+func openHead(path string) (*git.Repository, *plumbing.Reference, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open repository %s: %w", path, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, head, nil
 }
 
-func DeleteRepository(path string) error {
-	return os.RemoveAll(path)
+// DeleteRepository removes a clone from the cache root, paths outside of it are refused
+// so a user provided local path can never be deleted
+func DeleteRepository(root string, path string) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to delete %s outside of the clone cache %s", path, root)
+	}
+	return os.RemoveAll(absPath)
 }
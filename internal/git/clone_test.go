@@ -0,0 +1,118 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestDeleteRepository(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "tmp")
+	local := filepath.Join(dir, "servers", "brave")
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "clone in the cache", path: filepath.Join(root, "brave", "github.com", "hub", "brave.git", "main")},
+		{name: "user local path", path: local, wantErr: true},
+		{name: "cache root", path: root, wantErr: true},
+		{name: "escape with dot segments", path: filepath.Join(root, "..", "servers", "brave"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, path := range []string{tt.path, local} {
+				if err := os.MkdirAll(path, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := DeleteRepository(root, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %t", err, tt.wantErr)
+			}
+			_, statErr := os.Stat(tt.path)
+			if removed := os.IsNotExist(statErr); removed == tt.wantErr {
+				t.Errorf("removed %t, want %t", removed, !tt.wantErr)
+			}
+			if _, err := os.Stat(local); err != nil {
+				t.Errorf("local path removed: %v", err)
+			}
+		})
+	}
+}
+
+// commitFixture creates a git repository with a commit per date on main, and returns its path and commits
+func commitFixture(t *testing.T, dates ...time.Time) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commits []string
+	for i, date := range dates {
+		if err := os.WriteFile(filepath.Join(dir, "index.js"), []byte(fmt.Sprintf("console.log(%d)", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Add("index.js"); err != nil {
+			t.Fatal(err)
+		}
+		signature := &object.Signature{Name: "hub", Email: "hub@example.com", When: date}
+		hash, err := worktree.Commit(fmt.Sprintf("commit %d", i), &git.CommitOptions{Author: signature, Committer: signature})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, hash.String())
+	}
+	return dir, commits
+}
+
+func TestCommitEpoch(t *testing.T) {
+	first := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	url, commits := commitFixture(t, first, second)
+	tests := []struct {
+		name   string
+		commit string
+		want   time.Time
+	}{
+		{name: "branch head", want: second},
+		{name: "pinned commit", commit: commits[0], want: first},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "clone")
+			if _, err := CloneRepository(path, "main", tt.commit, url, ""); err != nil {
+				t.Fatal(err)
+			}
+			epoch, err := CommitEpoch(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if epoch != tt.want.Unix() {
+				t.Errorf("epoch = %d, want %d", epoch, tt.want.Unix())
+			}
+		})
+	}
+
+	// The parents of a path are never used, a directory of a clone has no commit of its own
+	if err := os.Mkdir(filepath.Join(url, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CommitEpoch(filepath.Join(url, "src")); err == nil {
+		t.Error("expected an error outside of the root of a repository")
+	}
+}
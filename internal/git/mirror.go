@@ -0,0 +1,62 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+const mirrorRemoteName = "mirror"
+
+// MirrorURL returns the URL of a repository in the mirror, its host and path appended to the mirror:
+// https://github.com/org/repo.git -> <mirror>/github.com/org/repo.git
+func MirrorURL(mirror string, repositoryURL string) (string, error) {
+	var host, path string
+	if u, err := url.Parse(repositoryURL); err == nil && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(repositoryURL, ":"); ok && !strings.Contains(at, "/") {
+		// scp-like syntax, git@github.com:org/repo.git
+		_, host, _ = strings.Cut(at, "@")
+		if host == "" {
+			host = at
+		}
+		path = rest
+	} else {
+		return "", fmt.Errorf("no host in repository URL %s", repositoryURL)
+	}
+	path = strings.Trim(path, "/")
+	if host == "" || path == "" {
+		return "", fmt.Errorf("no host or path in repository URL %s", repositoryURL)
+	}
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(mirror, "/"), host, path), nil
+}
+
+// PushMirror pushes the cloned branch of the repository at path to its mirror, overwriting the mirror branch
+func PushMirror(path string, branch string, mirrorURL string, proxy string) error {
+	proxyOptions, err := ProxyOptions(proxy, mirrorURL)
+	if err != nil {
+		return err
+	}
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return err
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: mirrorRemoteName, URLs: []string{mirrorURL}}); err != nil && !errors.Is(err, git.ErrRemoteExists) {
+		return err
+	}
+	ref := plumbing.NewBranchReferenceName(branch)
+	err = repo.Push(&git.PushOptions{
+		RemoteName:   mirrorRemoteName,
+		RefSpecs:     []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))},
+		ProxyOptions: proxyOptions,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("push to mirror %s: %w", mirrorURL, err)
+	}
+	return nil
+}
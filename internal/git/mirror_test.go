@@ -0,0 +1,30 @@
+package git
+
+import "testing"
+
+func TestMirrorURL(t *testing.T) {
+	tests := []struct {
+		repository string
+		want       string
+		wantErr    bool
+	}{
+		{repository: "https://github.com/hub/brave.git", want: "https://git.internal/mirror/github.com/hub/brave.git"},
+		{repository: "https://user@gitlab.com:8443/group/sub/brave/", want: "https://git.internal/mirror/gitlab.com/group/sub/brave"},
+		{repository: "ssh://git@github.com/hub/brave.git", want: "https://git.internal/mirror/github.com/hub/brave.git"},
+		{repository: "git@github.com:hub/brave.git", want: "https://git.internal/mirror/github.com/hub/brave.git"},
+		{repository: "github.com:hub/brave.git", want: "https://git.internal/mirror/github.com/hub/brave.git"},
+		{repository: "https://github.com/", wantErr: true},
+		{repository: "/srv/git/brave.git", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repository, func(t *testing.T) {
+			got, err := MirrorURL("https://git.internal/mirror/", tt.repository)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
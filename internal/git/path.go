@@ -0,0 +1,53 @@
+package git
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const githubHost = "github.com"
+
+// invalidPathChars are not allowed in file names on Windows
+var invalidPathChars = strings.NewReplacer("<", "_", ">", "_", ":", "_", "\"", "_", "\\", "_", "|", "_", "?", "_", "*", "_")
+
+// CachePath returns the directory a repository is cloned to under root.
+// GitHub repositories keep the owner/repo/branch layout, other hosts are prefixed by their host.
+func CachePath(root string, repositoryURL string, branch string) string {
+	segments := []string{root}
+
+	var path string
+	if u, err := url.Parse(repositoryURL); err == nil && u.Host != "" {
+		if u.Host != githubHost {
+			segments = append(segments, sanitizeSegment(u.Host))
+		}
+		path = u.Path
+	} else {
+		path = repositoryURL
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment = sanitizeSegment(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	for _, segment := range strings.Split(branch, "/") {
+		if segment = sanitizeSegment(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return filepath.Join(segments...)
+}
+
+func sanitizeSegment(segment string) string {
+	segment = invalidPathChars.Replace(segment)
+	segment = strings.Map(func(r rune) rune {
+		if r < 0x20 {
+			return '_'
+		}
+		return r
+	}, segment)
+	// Windows does not allow names ending with a dot or a space, and . or .. would escape the cache
+	segment = strings.TrimRight(segment, ". ")
+	return segment
+}
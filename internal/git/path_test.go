@@ -0,0 +1,55 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCachePath(t *testing.T) {
+	root := filepath.Join("tmp", "brave")
+	tests := []struct {
+		name   string
+		url    string
+		branch string
+		want   []string
+	}{
+		{
+			name:   "github",
+			url:    "https://github.com/smithery-ai/reference-servers.git",
+			branch: "main",
+			want:   []string{"smithery-ai", "reference-servers.git", "main"},
+		},
+		{
+			name:   "other host with a port",
+			url:    "https://git.example.com:8443/hub/servers",
+			branch: "feature/search",
+			want:   []string{"git.example.com_8443", "hub", "servers", "feature", "search"},
+		},
+		{
+			name:   "dot segments can't escape the cache",
+			url:    "https://github.com/hub/../../etc",
+			branch: "../..",
+			want:   []string{"hub", "etc"},
+		},
+		{
+			name:   "trailing dots and spaces",
+			url:    "https://github.com/hub/servers. ",
+			branch: "release.",
+			want:   []string{"hub", "servers", "release"},
+		},
+		{
+			name:   "characters invalid on windows",
+			url:    "git@github.com:hub/servers.git",
+			branch: `fix|"quotes"*`,
+			want:   []string{"git@github.com_hub", "servers.git", "fix__quotes__"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := filepath.Join(append([]string{root}, tt.want...)...)
+			if got := CachePath(root, tt.url, tt.branch); got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// ProxyOptions returns the proxy to use to reach the repository url.
+// The override takes precedence over the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func ProxyOptions(override string, repositoryURL string) (transport.ProxyOptions, error) {
+	if override != "" {
+		return transport.ProxyOptions{URL: override}, nil
+	}
+
+	target, err := url.Parse(repositoryURL)
+	if err != nil {
+		return transport.ProxyOptions{}, err
+	}
+	proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: target})
+	if err != nil || proxyURL == nil {
+		return transport.ProxyOptions{}, err
+	}
+
+	options := transport.ProxyOptions{URL: proxyURL.Scheme + "://" + proxyURL.Host}
+	if proxyURL.User != nil {
+		options.Username = proxyURL.User.Username()
+		options.Password, _ = proxyURL.User.Password()
+	}
+	return options, nil
+}
@@ -0,0 +1,30 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// ResolveBranch returns the commit the branch of the remote repository points to, without cloning it
+func ResolveBranch(url string, branch string, proxy string) (string, error) {
+	proxyOptions, err := ProxyOptions(proxy, url)
+	if err != nil {
+		return "", err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{url}})
+	refs, err := remote.List(&git.ListOptions{ProxyOptions: proxyOptions})
+	if err != nil {
+		return "", fmt.Errorf("list references of %s: %w", url, err)
+	}
+	name := plumbing.NewBranchReferenceName(branch)
+	for _, ref := range refs {
+		if ref.Name() == name {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", fmt.Errorf("branch %s not found in %s", branch, url)
+}
@@ -0,0 +1,55 @@
+package hub
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v2"
+)
+
+// GroupsFile is the config naming groups of repositories, selected at once with --group
+const GroupsFile = "_groups.yaml"
+
+// readGroups reads the groups of the config directory, the file is optional
+func (h *Hub) readGroups() error {
+	content, err := os.ReadFile(filepath.Join(h.Path, GroupsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var file struct {
+		Groups map[string][]string `yaml:"groups"`
+	}
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return fmt.Errorf("%s: %w", GroupsFile, err)
+	}
+	h.Groups = file.Groups
+	return nil
+}
+
+// validateGroups returns an error per group member that is not a repository of the hub
+func (h *Hub) validateGroups() []error {
+	var errs []error
+	for _, group := range slices.Sorted(maps.Keys(h.Groups)) {
+		for _, member := range h.Groups[group] {
+			if _, ok := h.Repositories[member]; !ok {
+				errs = append(errs, fmt.Errorf("group %s: unknown repository %s", group, member))
+			}
+		}
+	}
+	return errs
+}
+
+// Group returns the repositories of a group
+func (h *Hub) Group(name string) ([]string, error) {
+	members, ok := h.Groups[name]
+	if !ok {
+		return nil, fmt.Errorf("group %s not found in %s", name, GroupsFile)
+	}
+	return members, nil
+}
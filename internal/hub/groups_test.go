@@ -0,0 +1,54 @@
+package hub
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, map[string]string{
+		"grafana.yaml": "displayName: Grafana\n",
+		"sentry.yaml":  "displayName: Sentry\n",
+		GroupsFile:     "groups:\n  observability:\n    - grafana\n    - sentry\n  search:\n    - brave\n    - exa\n",
+	})
+	h := &Hub{}
+	if err := h.Read(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := h.Group("observability")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"grafana", "sentry"}; !reflect.DeepEqual(members, want) {
+		t.Errorf("got %v, want %v", members, want)
+	}
+	if _, err := h.Group("database"); err == nil || err.Error() != "group database not found in "+GroupsFile {
+		t.Errorf("err = %v, want the unknown group", err)
+	}
+
+	var got []string
+	for _, err := range h.validateGroups() {
+		got = append(got, err.Error())
+	}
+	if want := []string{"group search: unknown repository brave", "group search: unknown repository exa"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if err := h.ValidateWithDefaultValues(); err == nil || !strings.Contains(err.Error(), "group search: unknown repository brave") {
+		t.Errorf("err = %v, want the unknown members reported by the validation", err)
+	}
+}
+
+func TestGroupsMalformed(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, map[string]string{
+		"grafana.yaml": "displayName: Grafana\n",
+		GroupsFile:     "observability:\n  - grafana\n",
+	})
+	h := &Hub{}
+	if err := h.Read(dir); err == nil || !strings.HasPrefix(err.Error(), GroupsFile+": ") {
+		t.Errorf("err = %v, want the malformed groups file", err)
+	}
+}
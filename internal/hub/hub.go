@@ -1,19 +1,30 @@
 package hub
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	huberrors "github.com/blaxel-ai/mcp-hub/internal/errors"
 	"github.com/blaxel-ai/mcp-hub/internal/smithery"
 	"gopkg.in/yaml.v2"
 )
 
 type Hub struct {
 	Repositories map[string]*Repository `yaml:"repositories"`
+	// Groups are named lists of repositories, read from the groups file
+	Groups map[string][]string `yaml:"groups,omitempty"`
+	// Path is the local directory the hub was read from
+	Path string `yaml:"-"`
 }
 
 type PackageManager string
@@ -23,38 +34,136 @@ const (
 	PackageManagerAPT PackageManager = "apt"
 )
 
+const (
+	// VisibilityPublic repositories and fields are in every catalog
+	VisibilityPublic = "public"
+	// VisibilityInternal repositories and fields are only in the internal catalog
+	VisibilityInternal = "internal"
+)
+
 type Repository struct {
-	Repository      string                   `yaml:"repository" mendatory:"false"`
-	Path            string                   `yaml:"path" mendatory:"false"`
-	SmitheryPath    string                   `yaml:"smitheryPath" mendatory:"false" default:"smithery.yaml"`
-	Smithery        *smithery.SmitheryConfig `yaml:"smithery" mendatory:"false"`
-	Dockerfile      string                   `yaml:"dockerfile" mendatory:"false" default:"Dockerfile"`
-	PackageManager  PackageManager           `yaml:"packageManager" mendatory:"false" default:"apk"`
-	DoNotShow       []string                 `yaml:"doNotShow" mendatory:"false"`
-	HasNPM          bool                     `yaml:"hasNPM" mendatory:"false" default:"true"`
-	Branch          string                   `yaml:"branch" mendatory:"false" default:"main"`
-	URL             string                   `yaml:"url" mendatory:"false"`
-	DisplayName     string                   `yaml:"displayName" mendatory:"true"`
-	Icon            string                   `yaml:"icon" mendatory:"true"`
-	Disabled        bool                     `yaml:"disabled" mendatory:"false" default:"false"`
-	Description     string                   `yaml:"description" mendatory:"true"`
-	LongDescription string                   `yaml:"longDescription" mendatory:"true"`
-	Enterprise      bool                     `yaml:"enterprise" mendatory:"false" default:"false"`
-	ComingSoon      bool                     `yaml:"comingSoon" mendatory:"false" default:"false"`
-	Secrets         []string                 `yaml:"secrets" mendatory:"false"`
-	HiddenSecrets   []string                 `yaml:"hiddenSecrets" mendatory:"false"`
-	OAuth           *OAuth                   `yaml:"oauth" mendatory:"false"`
-	Integration     string                   `yaml:"integration" mendatory:"false"`
+	Repository      string                   `yaml:"repository" mandatory:"false"`
+	Path            string                   `yaml:"path" mandatory:"false"`
+	SmitheryPath    string                   `yaml:"smitheryPath" mandatory:"false" default:"smithery.yaml"`
+	Smithery        *smithery.SmitheryConfig `yaml:"smithery" mandatory:"false"`
+	Dockerfile      string                   `yaml:"dockerfile" mandatory:"false" default:"Dockerfile"`
+	Ignore          []string                 `yaml:"ignore" mandatory:"false"`
+	ExtraFiles      map[string]string        `yaml:"extraFiles" mandatory:"false"`
+	BuildTarget     string                   `yaml:"buildTarget" mandatory:"false"`
+	BuildNetwork    string                   `yaml:"buildNetwork" mandatory:"false"`
+	BuildContext    string                   `yaml:"buildContext" mandatory:"false"`
+	MaxImageSize    string                   `yaml:"maxImageSize" mandatory:"false"`
+	PreHooks        []string                 `yaml:"preHooks" mandatory:"false"`
+	PostHooks       []string                 `yaml:"postHooks" mandatory:"false"`
+	PackageManager  PackageManager           `yaml:"packageManager" mandatory:"false" default:"apk"`
+	DoNotShow       []string                 `yaml:"doNotShow" mandatory:"false"`
+	HasNPM          bool                     `yaml:"hasNPM" mandatory:"false" default:"true"`
+	Branch          string                   `yaml:"branch" mandatory:"false" default:"main"`
+	Commit          string                   `yaml:"commit" mandatory:"false"`
+	URL             string                   `yaml:"url" mandatory:"false"`
+	License         string                   `yaml:"license" mandatory:"true"`
+	DisplayName     string                   `yaml:"displayName" mandatory:"true"`
+	Icon            string                   `yaml:"icon" mandatory:"true"`
+	Disabled        bool                     `yaml:"disabled" mandatory:"false" default:"false"`
+	Description     string                   `yaml:"description" mandatory:"true"`
+	LongDescription string                   `yaml:"longDescription" mandatory:"true"`
+	Enterprise      bool                     `yaml:"enterprise" mandatory:"false" default:"false"`
+	ComingSoon      bool                     `yaml:"comingSoon" mandatory:"false" default:"false"`
+	Deprecated      bool                     `yaml:"deprecated" mandatory:"false" default:"false"`
+	ReplacedBy      string                   `yaml:"replacedBy" mandatory:"false"`
+	Secrets         []string                 `yaml:"secrets" mandatory:"false"`
+	HiddenSecrets   []string                 `yaml:"hiddenSecrets" mandatory:"false"`
+	OAuth           *OAuth                   `yaml:"oauth" mandatory:"false"`
+	Run             Run                      `yaml:"run" mandatory:"false"`
+	Integration     string                   `yaml:"integration" mandatory:"false"`
+	Visibility      string                   `yaml:"visibility" mandatory:"false" default:"public"`
+	InternalFields  []string                 `yaml:"internalFields" mandatory:"false"`
 	Tags            []string                 `yaml:"tags"`
 	Categories      []string                 `yaml:"categories"`
 }
 
+// Run configures the container started for the MCP
+type Run struct {
+	User           string   `yaml:"user"`
+	ReadOnlyRootfs bool     `yaml:"readOnlyRootfs"`
+	Tmpfs          []string `yaml:"tmpfs"`
+	// Init runs an init process reaping the zombies and forwarding the signals, enabled when not set
+	Init *bool `yaml:"init"`
+	// Memory is the memory limit of the container in the docker format, e.g. 512m, unlimited when empty
+	Memory string `yaml:"memory"`
+	// CPUs is the number of CPUs the container can use, unlimited when 0
+	CPUs float64 `yaml:"cpus"`
+}
+
+// InitEnabled returns whether the container runs with an init process
+func (r Run) InitEnabled() bool {
+	return r.Init == nil || *r.Init
+}
+
+// Validate rejects the run options that would obviously break the server
+func (r Run) Validate() error {
+	if strings.ContainsAny(r.User, " \t") {
+		return fmt.Errorf("invalid run user %q, expected <name|uid>[:<group|gid>]", r.User)
+	}
+	for _, path := range r.Tmpfs {
+		mount, _, _ := strings.Cut(path, ":")
+		if !strings.HasPrefix(mount, "/") {
+			return fmt.Errorf("invalid tmpfs path %s, it must be absolute", path)
+		}
+	}
+	if r.Memory != "" && !validMemory(r.Memory) {
+		return fmt.Errorf("invalid memory %s, expected a positive number with an optional b, k, m or g unit", r.Memory)
+	}
+	if r.CPUs < 0 {
+		return fmt.Errorf("invalid cpus %g, it must be positive", r.CPUs)
+	}
+	// npx and the gateway need somewhere to write their cache
+	if r.ReadOnlyRootfs && len(r.Tmpfs) == 0 {
+		return errors.New("readOnlyRootfs requires at least one tmpfs path, e.g. /tmp")
+	}
+	return nil
+}
+
+// networkPattern matches a docker network name, which includes default, none and host
+var networkPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// sizePattern matches a docker size, e.g. 512m or 2gb
+var sizePattern = regexp.MustCompile(`^(?i)([0-9]+(?:\.[0-9]+)?)([bkmg]|[kmg]b)?$`)
+
+// validMemory returns true for a positive docker memory size
+func validMemory(memory string) bool {
+	_, err := ParseSize(memory)
+	return err == nil
+}
+
+// ParseSize returns the bytes of a positive size in the docker format, a number with an optional b, k, m or g unit
+func ParseSize(size string) (int64, error) {
+	match := sizePattern.FindStringSubmatch(size)
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %s, expected a positive number with an optional b, k, m or g unit", size)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid size %s, it must be positive", size)
+	}
+	switch strings.ToLower(match[2][:min(len(match[2]), 1)]) {
+	case "k":
+		value *= 1 << 10
+	case "m":
+		value *= 1 << 20
+	case "g":
+		value *= 1 << 30
+	}
+	return int64(value), nil
+}
+
 type OAuth struct {
 	Type   string   `yaml:"type"`
 	Scopes []string `yaml:"scopes"`
 }
 
 func (h *Hub) Read(path string) error {
+	h.Path = path
 	h.Repositories = make(map[string]*Repository)
 	files, err := os.ReadDir(path)
 	if err != nil {
@@ -62,7 +171,7 @@ func (h *Hub) Read(path string) error {
 	}
 
 	for _, file := range files {
-		if file.IsDir() {
+		if file.IsDir() || !isRepositoryFile(file.Name()) {
 			continue
 		}
 
@@ -71,16 +180,59 @@ func (h *Hub) Read(path string) error {
 			return err
 		}
 
-		var repo Repository
-		if err := yaml.Unmarshal(yamlFile, &repo); err != nil {
-			return err
+		documents, err := decodeDocuments(yamlFile)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.Name(), err)
 		}
+		for _, document := range documents {
+			// Use filename without extension as repository name, unless the file holds several of them
+			name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+			if len(documents) > 1 {
+				if document.Name == "" {
+					return fmt.Errorf("%s: every document of a multi-document file requires a name", file.Name())
+				}
+				name = document.Name
+			}
+			if _, ok := h.Repositories[name]; ok {
+				return fmt.Errorf("%s: repository %s is defined twice", file.Name(), name)
+			}
+			repo := document.Repository
+			if err := repo.ExpandTemplates(name); err != nil {
+				return &huberrors.ValidationError{Repository: name, Err: err}
+			}
+			h.Repositories[name] = &repo
+		}
+	}
+	return h.readGroups()
+}
+
+// namedRepository is a document of a repository file, the name is only used by multi-document files
+type namedRepository struct {
+	Name       string `yaml:"name"`
+	Repository `yaml:",inline"`
+}
 
-		// Use filename without extension as repository name
-		name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
-		h.Repositories[name] = &repo
+// decodeDocuments returns the repositories of the --- separated documents of a file, skipping the empty ones
+func decodeDocuments(content []byte) ([]namedRepository, error) {
+	var documents []namedRepository
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document *namedRepository
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if document != nil {
+			documents = append(documents, *document)
+		}
 	}
-	return nil
+	if len(documents) == 0 {
+		documents = append(documents, namedRepository{})
+	}
+	return documents, nil
 }
 
 // ValidateWithDefaultValues validates the hub and applies default values to empty fields
@@ -90,35 +242,195 @@ func (h *Hub) ValidateWithDefaultValues() error {
 		return errors.New("repositories is required")
 	}
 
+	errs := misspelledMandatoryTags(reflect.TypeOf(Repository{}))
+	errs = append(errs, h.validateGroups()...)
+
+	// Sorted so the joined errors are the same across runs, the field errors follow the struct order
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		repository := h.Repositories[name]
+		if err := repository.ValidateAndApplyDefaults(); err != nil {
+			// A joined error is reported field by field, any other error as a whole
+			fieldErrs := []error{err}
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				fieldErrs = joined.Unwrap()
+			}
+			for _, err := range fieldErrs {
+				errs = append(errs, &huberrors.ValidationError{Repository: name, Err: err})
+			}
+		}
+		if err := h.validateReplacedBy(name, repository); err != nil {
+			errs = append(errs, &huberrors.ValidationError{Repository: name, Err: err})
+		}
+		if err := h.resolveExtraFiles(repository); err != nil {
+			errs = append(errs, &huberrors.ValidationError{Repository: name, Err: fmt.Errorf("field ExtraFiles is invalid: %w", err)})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateAndApplyDefaults validates the repository and applies default values to empty fields,
+// the returned error joins one error per invalid field
+func (r *Repository) ValidateAndApplyDefaults() error {
 	var errs []error
 
-	for name, repository := range h.Repositories {
-		// Use reflection to validate struct tags
-		v := reflect.ValueOf(repository).Elem() // Get the element the pointer refers to
-		t := v.Type()
+	// Use reflection to validate struct tags
+	v := reflect.ValueOf(r).Elem() // Get the element the pointer refers to
+	t := v.Type()
 
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			value := v.Field(i)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
 
-			// Check mandatory fields
-			if mandatory, ok := field.Tag.Lookup("mendatory"); ok && mandatory == "true" {
-				if value.IsZero() {
-					errs = append(errs, fmt.Errorf("field %s is required in repository %s", field.Name, name))
-				}
+		// Check mandatory fields
+		if mandatory, _ := mandatoryTag(field); mandatory == "true" {
+			if value.IsZero() {
+				errs = append(errs, fmt.Errorf("field %s is required", field.Name))
 			}
+		}
 
-			// Apply default values for empty fields
-			if defaultVal, ok := field.Tag.Lookup("default"); ok && value.IsZero() {
-				switch value.Kind() {
-				case reflect.String:
-					value.SetString(defaultVal)
-				case reflect.Bool:
-					value.SetBool(defaultVal == "true")
-				}
+		// Apply default values for empty fields
+		if defaultVal, ok := field.Tag.Lookup("default"); ok && value.IsZero() {
+			switch value.Kind() {
+			case reflect.String:
+				value.SetString(defaultVal)
+			case reflect.Bool:
+				value.SetBool(defaultVal == "true")
 			}
 		}
 	}
 
+	if err := r.Run.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("field Run is invalid: %w", err))
+	}
+	if r.Visibility != VisibilityPublic && r.Visibility != VisibilityInternal {
+		errs = append(errs, fmt.Errorf("field Visibility must be %s or %s", VisibilityPublic, VisibilityInternal))
+	}
+	if r.License != "" {
+		if err := ValidateLicense(r.License); err != nil {
+			errs = append(errs, fmt.Errorf("field License is invalid: %w", err))
+		}
+	}
+	if r.BuildNetwork != "" && !networkPattern.MatchString(r.BuildNetwork) {
+		errs = append(errs, fmt.Errorf("field BuildNetwork is invalid: %q is not default, none, host or a network name", r.BuildNetwork))
+	}
+	if r.MaxImageSize != "" {
+		if _, err := ParseSize(r.MaxImageSize); err != nil {
+			errs = append(errs, fmt.Errorf("field MaxImageSize is invalid: %w", err))
+		}
+	}
+	if r.BuildContext != "" && !filepath.IsLocal(r.BuildContext) {
+		errs = append(errs, errors.New("field BuildContext must be a relative path inside the repository"))
+	}
+	if r.BuildTarget != "" && strings.TrimSpace(r.BuildTarget) == "" {
+		errs = append(errs, errors.New("field BuildTarget can't be blank"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// mandatoryTag returns the mandatory tag of a field, the historical misspelling mendatory is still read
+func mandatoryTag(field reflect.StructField) (string, bool) {
+	if mandatory, ok := field.Tag.Lookup("mandatory"); ok {
+		return mandatory, true
+	}
+	return field.Tag.Lookup("mendatory")
+}
+
+// misspelledMandatoryTags returns an error for every field only tagged with the mendatory misspelling,
+// both spellings can be set on a field while migrating
+func misspelledMandatoryTags(t reflect.Type) []error {
+	var errs []error
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup("mandatory"); ok {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("mendatory"); ok {
+			errs = append(errs, fmt.Errorf("field %s of %s is tagged mendatory, use mandatory", field.Name, t.Name()))
+		}
+	}
+	return errs
+}
+
+// validateReplacedBy checks the replacement of a deprecated repository is another repository of the hub
+func (h *Hub) validateReplacedBy(name string, repository *Repository) error {
+	if repository.ReplacedBy == "" {
+		return nil
+	}
+	if !repository.Deprecated {
+		return errors.New("field ReplacedBy requires deprecated to be true")
+	}
+	if repository.ReplacedBy == name {
+		return errors.New("field ReplacedBy can't be the repository itself")
+	}
+	if _, ok := h.Repositories[repository.ReplacedBy]; !ok {
+		return fmt.Errorf("field ReplacedBy is invalid: unknown repository %s", repository.ReplacedBy)
+	}
+	return nil
+}
+
+// resolveExtraFiles checks the extra files of a repository exist, relative sources are resolved from the config directory
+func (h *Hub) resolveExtraFiles(repository *Repository) error {
+	var errs []error
+	for _, dst := range slices.Sorted(maps.Keys(repository.ExtraFiles)) {
+		if !filepath.IsLocal(dst) {
+			errs = append(errs, fmt.Errorf("destination %s must be a relative path inside the build context", dst))
+			continue
+		}
+		src := repository.ExtraFiles[dst]
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(h.Path, src)
+		}
+		info, err := os.Stat(src)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("source of %s: %w", dst, err))
+			continue
+		}
+		if info.IsDir() {
+			errs = append(errs, fmt.Errorf("source %s of %s is a directory", src, dst))
+			continue
+		}
+		repository.ExtraFiles[dst] = src
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateLongDescription checks that no long description exceeds maxLength characters, 0 disables the check
+func (h *Hub) ValidateLongDescription(maxLength int) error {
+	if maxLength <= 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		length := len([]rune(h.Repositories[name].LongDescription))
+		if length > maxLength {
+			errs = append(errs, fmt.Errorf("field LongDescription is too long in repository %s: %d characters, max %d", name, length, maxLength))
+		}
+	}
 	return errors.Join(errs...)
 }
+
+// InCatalog returns true when the repository must appear in the generated catalog,
+// disabled repositories are only included on request
+func (r *Repository) InCatalog(includeDisabled bool) bool {
+	return !r.Disabled || includeDisabled
+}
+
+// OverrideRef replaces the branch and commit cloned for the repository, empty values are ignored
+func (r *Repository) OverrideRef(branch string, commit string) error {
+	if branch == "" && commit == "" {
+		return nil
+	}
+	if r.Path != "" {
+		return fmt.Errorf("cannot override the branch or commit of a repository built from a local path")
+	}
+	if branch != "" {
+		r.Branch = branch
+	}
+	if commit != "" {
+		r.Commit = commit
+	}
+	return nil
+}
@@ -0,0 +1,342 @@
+package hub
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateLongDescription(t *testing.T) {
+	h := &Hub{Repositories: map[string]*Repository{
+		"short": {LongDescription: "Search the web"},
+		"exact": {LongDescription: strings.Repeat("é", 20)},
+		"long":  {LongDescription: strings.Repeat("a", 21)},
+	}}
+	tests := []struct {
+		name      string
+		maxLength int
+		want      string
+	}{
+		{name: "limit exceeded", maxLength: 20, want: "field LongDescription is too long in repository long: 21 characters, max 20"},
+		{name: "check disabled", maxLength: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.ValidateLongDescription(tt.maxLength)
+			if got := errorString(err); got != tt.want {
+				t.Errorf("err = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// errorString returns the message of err, empty when nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestOverrideRef(t *testing.T) {
+	repository := &Repository{Repository: "https://github.com/hub/servers.git", Branch: "main"}
+	if err := repository.OverrideRef("", ""); err != nil || repository.Branch != "main" {
+		t.Fatalf("empty override changed the ref: %v %+v", err, repository)
+	}
+	if err := repository.OverrideRef("fix", "abc123"); err != nil || repository.Branch != "fix" || repository.Commit != "abc123" {
+		t.Fatalf("override not applied: %v %+v", err, repository)
+	}
+	local := &Repository{Path: "servers/"}
+	if err := local.OverrideRef("fix", ""); err == nil {
+		t.Error("expected an error for a local path")
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		run  Run
+		want string
+	}{
+		{name: "no options"},
+		{name: "non-root read-only", run: Run{User: "node", ReadOnlyRootfs: true, Tmpfs: []string{"/tmp:size=64m"}}},
+		{name: "user with a space", run: Run{User: "node user"}, want: `invalid run user "node user", expected <name|uid>[:<group|gid>]`},
+		{name: "relative tmpfs", run: Run{Tmpfs: []string{"tmp"}}, want: "invalid tmpfs path tmp, it must be absolute"},
+		{name: "read-only without tmpfs", run: Run{ReadOnlyRootfs: true}, want: "readOnlyRootfs requires at least one tmpfs path, e.g. /tmp"},
+		{name: "resource limits", run: Run{Memory: "1.5g", CPUs: 0.5}},
+		{name: "memory without a number", run: Run{Memory: "lots"}, want: "invalid memory lots, expected a positive number with an optional b, k, m or g unit"},
+		{name: "negative cpus", run: Run{CPUs: -1}, want: "invalid cpus -1, it must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorString(tt.run.Validate()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWithDefaultValuesOrder(t *testing.T) {
+	valid := func() *Repository {
+		return &Repository{
+			License:         "MIT",
+			DisplayName:     "Brave Search",
+			Icon:            "https://brave.com/logo.svg",
+			Description:     "Search the web.",
+			LongDescription: "Search the web using Brave's search engine.",
+		}
+	}
+	zeta := valid()
+	zeta.DisplayName, zeta.Description = "", ""
+	alpha := valid()
+	alpha.Icon = ""
+	alpha.Run.Tmpfs = []string{"tmp"}
+	h := &Hub{Repositories: map[string]*Repository{"zeta": zeta, "mid": valid(), "alpha": alpha}}
+
+	want := "repository alpha: field Icon is required\n" +
+		"repository alpha: field Run is invalid: invalid tmpfs path tmp, it must be absolute\n" +
+		"repository zeta: field DisplayName is required\n" +
+		"repository zeta: field Description is required"
+	for range 10 {
+		if got := errorString(h.ValidateWithDefaultValues()); got != want {
+			t.Fatalf("got\n%s\nwant\n%s", got, want)
+		}
+	}
+}
+
+func TestValidateAndApplyDefaults(t *testing.T) {
+	t.Run("defaults applied", func(t *testing.T) {
+		r := &Repository{
+			License:         "MIT",
+			DisplayName:     "Brave Search",
+			Icon:            "https://brave.com/logo.svg",
+			Description:     "Search the web.",
+			LongDescription: "Search the web using Brave's search engine.",
+			Branch:          "develop",
+		}
+		if err := r.ValidateAndApplyDefaults(); err != nil {
+			t.Fatal(err)
+		}
+		want := Repository{
+			License:         "MIT",
+			DisplayName:     "Brave Search",
+			Icon:            "https://brave.com/logo.svg",
+			Description:     "Search the web.",
+			LongDescription: "Search the web using Brave's search engine.",
+			SmitheryPath:    "smithery.yaml",
+			Dockerfile:      "Dockerfile",
+			PackageManager:  PackageManagerAPK,
+			HasNPM:          true,
+			Branch:          "develop",
+			Visibility:      VisibilityPublic,
+		}
+		if !reflect.DeepEqual(*r, want) {
+			t.Errorf("got  %+v\nwant %+v", *r, want)
+		}
+	})
+
+	t.Run("required fields", func(t *testing.T) {
+		r := &Repository{PackageManager: PackageManagerAPT}
+		err := r.ValidateAndApplyDefaults()
+		want := "field License is required\n" +
+			"field DisplayName is required\n" +
+			"field Icon is required\n" +
+			"field Description is required\n" +
+			"field LongDescription is required"
+		if got := errorString(err); got != want {
+			t.Fatalf("got\n%s\nwant\n%s", got, want)
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 5 {
+			t.Errorf("err = %#v, want one joined error per field", err)
+		}
+		if r.PackageManager != PackageManagerAPT || r.Branch != "main" {
+			t.Errorf("package manager %q and branch %q, want the set value kept and the default applied", r.PackageManager, r.Branch)
+		}
+	})
+}
+
+func TestValidateReplacedBy(t *testing.T) {
+	tests := []struct {
+		name       string
+		deprecated bool
+		replacedBy string
+		wantErr    string
+	}{
+		{name: "not deprecated"},
+		{name: "deprecated without replacement", deprecated: true},
+		{name: "replaced by another repository", deprecated: true, replacedBy: "brave-v2"},
+		{name: "replacement of a repository not deprecated", replacedBy: "brave-v2", wantErr: "repository brave: field ReplacedBy requires deprecated to be true"},
+		{name: "replaced by itself", deprecated: true, replacedBy: "brave", wantErr: "repository brave: field ReplacedBy can't be the repository itself"},
+		{name: "unknown replacement", deprecated: true, replacedBy: "brave-v3", wantErr: "repository brave: field ReplacedBy is invalid: unknown repository brave-v3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := func() *Repository {
+				return &Repository{
+					License:         "MIT",
+					DisplayName:     "Brave Search",
+					Icon:            "https://brave.com/logo.svg",
+					Description:     "Search the web.",
+					LongDescription: "Search the web using Brave's search engine.",
+				}
+			}
+			brave := repository()
+			brave.Deprecated, brave.ReplacedBy = tt.deprecated, tt.replacedBy
+			h := &Hub{Repositories: map[string]*Repository{"brave": brave, "brave-v2": repository()}}
+			if got := errorString(h.ValidateWithDefaultValues()); got != tt.wantErr {
+				t.Errorf("got %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBuildNetwork(t *testing.T) {
+	tests := []struct {
+		network string
+		wantErr string
+	}{
+		{network: ""},
+		{network: "default"},
+		{network: "none"},
+		{network: "host"},
+		{network: "hub_private-registry.1"},
+		{network: "my network", wantErr: `field BuildNetwork is invalid: "my network" is not default, none, host or a network name`},
+		{network: "-host", wantErr: `field BuildNetwork is invalid: "-host" is not default, none, host or a network name`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.network, func(t *testing.T) {
+			r := &Repository{
+				License:         "MIT",
+				DisplayName:     "Brave Search",
+				Icon:            "https://brave.com/logo.svg",
+				Description:     "Search the web.",
+				LongDescription: "Search the web using Brave's search engine.",
+				BuildNetwork:    tt.network,
+			}
+			if got := errorString(r.ValidateAndApplyDefaults()); got != tt.wantErr {
+				t.Errorf("err = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "512", want: 512},
+		{size: "64k", want: 64 << 10},
+		{size: "500MB", want: 500 << 20},
+		{size: "1.5g", want: 3 << 29},
+		{size: "0m", wantErr: true},
+		{size: "-1g", wantErr: true},
+		{size: "1t", wantErr: true},
+		{size: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := ParseSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMultiDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "three repositories",
+			files: map[string]string{
+				"search.yaml": "name: brave\ndisplayName: Brave Search\n---\nname: exa\ndisplayName: Exa\n---\nname: tavily\ndisplayName: Tavily\n",
+				"notion.yaml": "displayName: Notion\n",
+			},
+			want: []string{"brave", "exa", "notion", "tavily"},
+		},
+		{
+			name:    "missing name",
+			files:   map[string]string{"search.yaml": "name: brave\ndisplayName: Brave Search\n---\ndisplayName: Exa\n"},
+			wantErr: "search.yaml: every document of a multi-document file requires a name",
+		},
+		{
+			name: "duplicate name",
+			files: map[string]string{
+				"brave.yaml":  "displayName: Brave Search\n",
+				"search.yaml": "name: brave\ndisplayName: Brave Search\n---\nname: exa\ndisplayName: Exa\n",
+			},
+			wantErr: "search.yaml: repository brave is defined twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			h := &Hub{}
+			err := h.Read(dir)
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("err = %q, want %q", got, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := slices.Sorted(maps.Keys(h.Repositories)); !slices.Equal(got, tt.want) {
+				t.Errorf("repositories = %v, want %v", got, tt.want)
+			}
+			if h.Repositories["exa"].DisplayName != "Exa" {
+				t.Errorf("exa display name = %q", h.Repositories["exa"].DisplayName)
+			}
+		})
+	}
+}
+
+func TestMandatoryTags(t *testing.T) {
+	type legacy struct {
+		Name        string `mendatory:"true"`
+		DisplayName string `mandatory:"true"`
+		Icon        string `mandatory:"true" mendatory:"true"`
+		URL         string `mendatory:"false"`
+		Description string
+	}
+	typ := reflect.TypeOf(legacy{})
+	want := map[string]string{"Name": "true", "DisplayName": "true", "Icon": "true", "URL": "false"}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		got, ok := mandatoryTag(field)
+		if got != want[field.Name] || ok != (field.Name != "Description") {
+			t.Errorf("mandatoryTag(%s) = %q, %t", field.Name, got, ok)
+		}
+	}
+
+	var got []string
+	for _, err := range misspelledMandatoryTags(typ) {
+		got = append(got, err.Error())
+	}
+	wantErrs := []string{
+		"field Name of legacy is tagged mendatory, use mandatory",
+		"field URL of legacy is tagged mendatory, use mandatory",
+	}
+	if !slices.Equal(got, wantErrs) {
+		t.Errorf("got  %q\nwant %q", got, wantErrs)
+	}
+	if errs := misspelledMandatoryTags(reflect.TypeOf(Repository{})); len(errs) != 0 {
+		t.Errorf("Repository has misspelled tags: %v", errs)
+	}
+}
@@ -0,0 +1,75 @@
+package hub
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+//go:embed integrations.txt
+var embeddedIntegrations string
+
+// IntegrationSource provides the integrations a repository can be linked to
+type IntegrationSource interface {
+	Integrations() ([]string, error)
+}
+
+// EmbeddedIntegrations are the integrations shipped with the binary
+type EmbeddedIntegrations struct{}
+
+func (s EmbeddedIntegrations) Integrations() ([]string, error) {
+	return parseIntegrations(embeddedIntegrations), nil
+}
+
+// FileIntegrations reads the integrations from a file, one per line, # starts a comment
+type FileIntegrations struct {
+	Path string
+}
+
+func (s FileIntegrations) Integrations() ([]string, error) {
+	content, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseIntegrations(string(content)), nil
+}
+
+// NewIntegrationSource returns the file source when path is set, the embedded one otherwise
+func NewIntegrationSource(path string) IntegrationSource {
+	if path == "" {
+		return EmbeddedIntegrations{}
+	}
+	return FileIntegrations{Path: path}
+}
+
+func parseIntegrations(content string) []string {
+	var integrations []string
+	for _, line := range strings.Split(content, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if line = strings.TrimSpace(line); line != "" {
+			integrations = append(integrations, line)
+		}
+	}
+	return integrations
+}
+
+// ValidateIntegrations checks that every integration set on a repository is known by the source
+func (h *Hub) ValidateIntegrations(source IntegrationSource) error {
+	integrations, err := source.Integrations()
+	if err != nil {
+		return fmt.Errorf("failed to load integrations: %w", err)
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		integration := h.Repositories[name].Integration
+		if integration != "" && !slices.Contains(integrations, integration) {
+			errs = append(errs, fmt.Errorf("unknown integration %s in repository %s", integration, name))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,51 @@
+package hub
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateIntegrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "integrations.txt")
+	if err := os.WriteFile(path, []byte("# Search\nbrave-search\n\nnotion # docs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		source       IntegrationSource
+		integrations map[string]string
+		want         string
+	}{
+		{name: "known integration", source: FileIntegrations{Path: path}, integrations: map[string]string{"brave": "brave-search", "notion": "notion"}},
+		{name: "no integration", source: FileIntegrations{Path: path}, integrations: map[string]string{"brave": ""}},
+		{
+			name:         "unknown integrations sorted",
+			source:       FileIntegrations{Path: path},
+			integrations: map[string]string{"slack": "slack", "brave": "brave", "notion": "notion"},
+			want:         "unknown integration brave in repository brave\nunknown integration slack in repository slack",
+		},
+		{name: "embedded list", source: NewIntegrationSource(""), integrations: map[string]string{"brave": "brave-search"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Hub{Repositories: map[string]*Repository{}}
+			for name, integration := range tt.integrations {
+				h.Repositories[name] = &Repository{Integration: integration}
+			}
+			if got := errorString(h.ValidateIntegrations(tt.source)); got != tt.want {
+				t.Errorf("err = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateIntegrationsMissingFile(t *testing.T) {
+	h := &Hub{Repositories: map[string]*Repository{"brave": {Integration: "brave-search"}}}
+	err := h.ValidateIntegrations(FileIntegrations{Path: filepath.Join(t.TempDir(), "missing.txt")})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("err = %v, want the missing file", err)
+	}
+}
@@ -0,0 +1,191 @@
+package hub
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//go:embed spdx.txt
+var embeddedSPDX string
+
+// spdxLicenses are the lowercased SPDX identifiers of the embedded list
+var spdxLicenses = func() map[string]bool {
+	licenses := make(map[string]bool)
+	for _, license := range parseIntegrations(embeddedSPDX) {
+		licenses[strings.ToLower(license)] = true
+	}
+	return licenses
+}()
+
+// licenseExpression is a node of a parsed SPDX license expression, a license when op is empty and otherwise the AND
+// or OR of its operands
+type licenseExpression struct {
+	op       string
+	id       string
+	operands []*licenseExpression
+}
+
+// parseLicense parses a SPDX license expression, e.g. (MIT OR Apache-2.0) AND BSD-3-Clause. AND takes precedence over
+// OR, the exceptions following WITH are left out and the + of "or later" is stripped from the identifiers.
+func parseLicense(expression string) (*licenseExpression, error) {
+	p := &licenseParser{tokens: strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression))}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression %q", expression)
+	}
+	node, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", expression, err)
+	}
+	return node, nil
+}
+
+// licenseParser is a recursive descent parser of the tokens of a license expression
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+// next returns the uppercased operator or the token at the position, empty at the end
+func (p *licenseParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	if token := strings.ToUpper(p.tokens[p.pos]); token == "AND" || token == "OR" || token == "WITH" {
+		return token
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *licenseParser) parseOr() (*licenseExpression, error) {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *licenseParser) parseAnd() (*licenseExpression, error) {
+	return p.parseBinary("AND", p.parseTerm)
+}
+
+// parseBinary parses the operands of op, each parsed by operand
+func (p *licenseParser) parseBinary(op string, operand func() (*licenseExpression, error)) (*licenseExpression, error) {
+	node, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.next() != op {
+		return node, nil
+	}
+	node = &licenseExpression{op: op, operands: []*licenseExpression{node}}
+	for p.next() == op {
+		p.pos++
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		node.operands = append(node.operands, right)
+	}
+	return node, nil
+}
+
+// parseTerm parses a parenthesized expression or a license with its optional exception
+func (p *licenseParser) parseTerm() (*licenseExpression, error) {
+	switch token := p.next(); token {
+	case "":
+		return nil, errors.New("missing license at the end")
+	case "(":
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return node, nil
+	case ")", "AND", "OR", "WITH":
+		return nil, fmt.Errorf("unexpected %s", token)
+	default:
+		p.pos++
+		if p.next() == "WITH" {
+			p.pos++
+			switch exception := p.next(); exception {
+			case "", "(", ")", "AND", "OR", "WITH":
+				return nil, fmt.Errorf("missing exception after WITH %s", token)
+			}
+			p.pos++
+		}
+		return &licenseExpression{id: strings.TrimSuffix(token, "+")}, nil
+	}
+}
+
+// ids returns the license identifiers of the expression in order
+func (e *licenseExpression) ids() []string {
+	if e.op == "" {
+		return []string{e.id}
+	}
+	var ids []string
+	for _, operand := range e.operands {
+		ids = append(ids, operand.ids()...)
+	}
+	return ids
+}
+
+// allowed returns true when the expression can be satisfied with the licenses accepted by allow:
+// every operand of an AND, and at least one of an OR
+func (e *licenseExpression) allowed(allow func(id string) bool) bool {
+	switch e.op {
+	case "AND":
+		for _, operand := range e.operands {
+			if !operand.allowed(allow) {
+				return false
+			}
+		}
+		return true
+	case "OR":
+		return slices.ContainsFunc(e.operands, func(operand *licenseExpression) bool { return operand.allowed(allow) })
+	default:
+		return allow(e.id)
+	}
+}
+
+// licenseIDs returns the license identifiers of a SPDX license expression, e.g. (MIT OR Apache-2.0) AND BSD-3-Clause,
+// the exceptions following WITH are left out
+func licenseIDs(expression string) ([]string, error) {
+	node, err := parseLicense(expression)
+	if err != nil {
+		return nil, err
+	}
+	return node.ids(), nil
+}
+
+// ValidateLicense checks every identifier of a SPDX license expression is a known SPDX license,
+// LicenseRef- identifiers declare a license outside of the SPDX list
+func ValidateLicense(expression string) error {
+	ids, err := licenseIDs(expression)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !strings.HasPrefix(id, "LicenseRef-") && !spdxLicenses[strings.ToLower(id)] {
+			return fmt.Errorf("unknown SPDX license %s", id)
+		}
+	}
+	return nil
+}
+
+// LicenseAllowed returns true when the license expression of the repository is satisfied by the allowed licenses,
+// an OR needs one of its licenses allowed and an AND all of them
+func (r *Repository) LicenseAllowed(allowed []string) bool {
+	node, err := parseLicense(r.License)
+	if err != nil {
+		return false
+	}
+	return node.allowed(func(id string) bool {
+		return slices.ContainsFunc(allowed, func(license string) bool { return strings.EqualFold(license, id) })
+	})
+}
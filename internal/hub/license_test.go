@@ -0,0 +1,90 @@
+package hub
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLicenseIDs(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []string
+		wantErr    string
+	}{
+		{expression: "MIT", want: []string{"MIT"}},
+		{expression: "GPL-2.0+", want: []string{"GPL-2.0"}},
+		{expression: "MIT OR Apache-2.0", want: []string{"MIT", "Apache-2.0"}},
+		{expression: "(MIT OR Apache-2.0) AND BSD-3-Clause", want: []string{"MIT", "Apache-2.0", "BSD-3-Clause"}},
+		{expression: "GPL-2.0-only WITH Classpath-exception-2.0 or MIT", want: []string{"GPL-2.0-only", "MIT"}},
+		{expression: "((MIT))", want: []string{"MIT"}},
+		{expression: " ", wantErr: `empty license expression " "`},
+		{expression: "MIT AND", wantErr: `invalid license expression "MIT AND": missing license at the end`},
+		{expression: "MIT Apache-2.0", wantErr: `invalid license expression "MIT Apache-2.0": unexpected Apache-2.0`},
+		{expression: "(MIT OR Apache-2.0", wantErr: `invalid license expression "(MIT OR Apache-2.0": missing )`},
+		{expression: "OR MIT", wantErr: `invalid license expression "OR MIT": unexpected OR`},
+		{expression: "GPL-2.0-only WITH", wantErr: `invalid license expression "GPL-2.0-only WITH": missing exception after WITH GPL-2.0-only`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := licenseIDs(tt.expression)
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("err = %q, want %q", got, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLicense(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{expression: "MIT"},
+		{expression: "apache-2.0"},
+		{expression: "GPL-3.0-or-later"},
+		{expression: "GPL-2.0"},
+		{expression: "Elastic-2.0 OR LicenseRef-Commercial"},
+		{expression: "MIT AND Proprietary", wantErr: "unknown SPDX license Proprietary"},
+		{expression: "Apache 2.0", wantErr: `invalid license expression "Apache 2.0": unexpected 2.0`},
+		{expression: "", wantErr: `empty license expression ""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if got := errorString(ValidateLicense(tt.expression)); got != tt.wantErr {
+				t.Errorf("err = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLicenseAllowed(t *testing.T) {
+	allowed := []string{"MIT", "Apache-2.0"}
+	tests := []struct {
+		license string
+		want    bool
+	}{
+		{license: "MIT", want: true},
+		{license: "mit", want: true},
+		{license: "GPL-3.0-only", want: false},
+		{license: "MIT OR GPL-3.0-only", want: true},
+		{license: "GPL-3.0-only OR AGPL-3.0-only", want: false},
+		{license: "MIT AND Apache-2.0", want: true},
+		{license: "MIT AND GPL-3.0-only", want: false},
+		{license: "(MIT OR GPL-3.0-only) AND Apache-2.0", want: true},
+		{license: "MIT OR GPL-3.0-only AND AGPL-3.0-only", want: true},
+		{license: "GPL-3.0-only OR MIT AND AGPL-3.0-only", want: false},
+		{license: "Apache-2.0 WITH LLVM-exception", want: true},
+		{license: "MIT OR", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			r := &Repository{License: tt.license}
+			if got := r.LicenseAllowed(allowed); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package hub
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LockFile is the default path of the lockfile written by freeze
+const LockFile = "hub.lock"
+
+// Lock pins the commit cloned for every repository
+type Lock struct {
+	Repositories map[string]LockedRepository `yaml:"repositories"`
+}
+
+// LockedRepository is the commit a branch of a repository pointed to when the hub was frozen
+type LockedRepository struct {
+	Repository string `yaml:"repository"`
+	Branch     string `yaml:"branch"`
+	Commit     string `yaml:"commit"`
+}
+
+// ReadLock reads a lockfile
+func ReadLock(path string) (*Lock, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lock Lock
+	if err := yaml.UnmarshalStrict(content, &lock); err != nil {
+		return nil, fmt.Errorf("parse lockfile %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// Write writes the lockfile, the repositories are sorted by name
+func (l *Lock) Write(path string) error {
+	content, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// ApplyLock pins the repositories to the commits of the lock, every cloned repository must be in it
+func (r *Repository) ApplyLock(name string, lock *Lock) error {
+	if r.Path != "" {
+		return nil
+	}
+	locked, ok := lock.Repositories[name]
+	if !ok {
+		return fmt.Errorf("repository %s is not in the lockfile, run freeze again", name)
+	}
+	if locked.Repository != r.Repository {
+		return fmt.Errorf("repository %s is locked for %s but configured for %s, run freeze again", name, locked.Repository, r.Repository)
+	}
+	r.Branch = locked.Branch
+	r.Commit = locked.Commit
+	return nil
+}
@@ -0,0 +1,183 @@
+package hub
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const remoteMetadataFile = "metadata.json"
+
+type remoteMetadata struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"lastModified"`
+}
+
+// IsRemote returns true when the config path is an http(s) URL
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// ResolvePath returns the local directory holding the hub config.
+// Remote configs are downloaded and cached, local paths are returned as is.
+func ResolvePath(path string) (string, error) {
+	if !IsRemote(path) {
+		return path, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return Fetch(path, filepath.Join(cacheDir, "mcp-hub", "config"))
+}
+
+// Fetch downloads the tarball (optionally gzipped) at url into cacheDir and returns the extracted directory.
+// The ETag and Last-Modified of the previous download are sent, the cached copy is reused on 304.
+func Fetch(url string, cacheDir string) (string, error) {
+	sum := sha256.Sum256([]byte(url))
+	dir := filepath.Join(cacheDir, hex.EncodeToString(sum[:8]))
+	contentDir := filepath.Join(dir, "content")
+	metadataPath := filepath.Join(dir, remoteMetadataFile)
+
+	var metadata remoteMetadata
+	if data, err := os.ReadFile(metadataPath); err == nil {
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			metadata = remoteMetadata{}
+		}
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(contentDir); err == nil {
+		if metadata.ETag != "" {
+			req.Header.Set("If-None-Match", metadata.ETag)
+		}
+		if metadata.LastModified != "" {
+			req.Header.Set("If-Modified-Since", metadata.LastModified)
+		}
+	}
+
+	// The timeout covers the download of the tarball
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return configRoot(contentDir)
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("failed to fetch config %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	tmpDir, err := os.MkdirTemp(dir, "download-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := extractTarball(resp.Body, tmpDir); err != nil {
+		return "", fmt.Errorf("failed to extract config %s: %w", url, err)
+	}
+
+	if err := os.RemoveAll(contentDir); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpDir, contentDir); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(remoteMetadata{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(metadataPath, data, 0644); err != nil {
+		return "", err
+	}
+	return configRoot(contentDir)
+}
+
+// configRoot unwraps the single top level directory archives usually have
+func configRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
+
+func extractTarball(r io.Reader, dest string) error {
+	reader := bufio.NewReader(r)
+	// Gzip magic number
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return extractTar(gz, dest)
+	}
+	return extractTar(reader, dest)
+}
+
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, header.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dest)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, tr); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package hub
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarball returns a tar archive of the files, gzipped when compress is set
+func tarball(t *testing.T, files map[string]string, compress bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !compress {
+		return buf.Bytes()
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	if _, err := zw.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return gz.Bytes()
+}
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		compress bool
+		want     string
+	}{
+		{name: "tar", files: map[string]string{"hub/a.yaml": "a"}, want: "hub/a.yaml"},
+		{name: "gzipped tar", files: map[string]string{"hub/a.yaml": "a"}, compress: true, want: "hub/a.yaml"},
+		{name: "no top level directory", files: map[string]string{"a.yaml": "a", "b.yaml": "b"}, want: "b.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tarball(t, tt.files, tt.compress)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(body)
+			}))
+			defer server.Close()
+
+			dir, err := Fetch(server.URL, t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := os.ReadFile(filepath.Join(dir, filepath.Base(tt.want)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.files[tt.want]; string(content) != want {
+				t.Errorf("content = %q, want %q", content, want)
+			}
+		})
+	}
+}
+
+func TestFetchNotModified(t *testing.T) {
+	body := tarball(t, map[string]string{"hub/a.yaml": "a"}, false)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	first, err := Fetch(server.URL, cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Fetch(server.URL, cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || requests != 2 {
+		t.Errorf("got %s then %s in %d requests, want the cached copy", first, second, requests)
+	}
+	if _, err := os.Stat(filepath.Join(second, "a.yaml")); err != nil {
+		t.Errorf("cached config: %v", err)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   []byte
+	}{
+		{name: "server error", status: http.StatusInternalServerError},
+		{name: "not found", status: http.StatusNotFound},
+		{name: "path outside of the archive", status: http.StatusOK, body: tarball(t, map[string]string{"../escape.yaml": "x"}, false)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			if _, err := Fetch(server.URL, t.TempDir()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultsFile is the config shared by every repository, a change to it affects all of them
+const DefaultsFile = "_defaults.yaml"
+
+// ChangedSince returns the names of the repositories whose config file or local source path changed in the git
+// repository of the hub since ref. It returns nil when the shared defaults changed, as every repository is affected,
+// and an empty list rather than nil when none changed.
+func (h *Hub) ChangedSince(ref string) ([]string, error) {
+	top, err := gitOutput(h.Path, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("git repository of %s: %w", h.Path, err)
+	}
+	// Paths of the diff are relative to the top level so changes outside of the config directory are listed
+	output, err := gitOutput(top, "diff", "--name-only", ref)
+	if err != nil {
+		return nil, fmt.Errorf("git diff since %s: %w", ref, err)
+	}
+	configDir, err := relativePath(top, h.Path)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string)
+	for name, repository := range h.Repositories {
+		if repository.Path == "" {
+			continue
+		}
+		source, err := relativePath(top, repository.Path)
+		if err != nil {
+			return nil, err
+		}
+		// A source outside of the hub repository has no history to compare
+		if source != ".." && !strings.HasPrefix(source, "../") {
+			sources[name] = source
+		}
+	}
+
+	changed := make(map[string]bool)
+	for _, file := range strings.Split(output, "\n") {
+		if file == "" {
+			continue
+		}
+		// Repositories are only read from the top level of the config directory
+		if dir, base := path.Split(file); path.Clean(dir) == configDir {
+			if base == DefaultsFile {
+				return nil, nil
+			}
+			if isRepositoryFile(base) {
+				names, err := fileRepositories(filepath.Join(h.Path, base))
+				if err != nil {
+					return nil, err
+				}
+				for _, name := range names {
+					changed[name] = true
+				}
+			}
+		}
+		for name, source := range sources {
+			if source == "." || file == source || strings.HasPrefix(file, source+"/") {
+				changed[name] = true
+			}
+		}
+	}
+	names := slices.Sorted(maps.Keys(changed))
+	if names == nil {
+		names = []string{}
+	}
+	return names, nil
+}
+
+// fileRepositories returns the names of the repositories of a config file, read as Read does,
+// none when the file was deleted
+func fileRepositories(file string) ([]string, error) {
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	documents, err := decodeDocuments(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+	}
+	if len(documents) == 1 {
+		return []string{strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))}, nil
+	}
+	var names []string
+	for _, document := range documents {
+		if document.Name == "" {
+			return nil, fmt.Errorf("%s: every document of a multi-document file requires a name", filepath.Base(file))
+		}
+		names = append(names, document.Name)
+	}
+	return names, nil
+}
+
+// relativePath returns the slash separated path of target relative to the git top level directory,
+// symbolic links are resolved as git does for its top level
+func relativePath(top string, target string) (string, error) {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(top, abs)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// gitOutput runs git in dir and returns its trimmed output, the error holds the stderr of git
+func gitOutput(dir string, args ...string) (string, error) {
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// isRepositoryFile returns true when a file of the config directory is the config of a repository,
+// files starting with an underscore are shared by the repositories
+func isRepositoryFile(file string) bool {
+	ext := filepath.Ext(file)
+	return (ext == ".yaml" || ext == ".yml") && !strings.HasPrefix(file, "_")
+}
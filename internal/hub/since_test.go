@@ -0,0 +1,109 @@
+package hub
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// git runs a git command in dir with a fixed identity
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=hub", "-c", "user.email=hub@example.com"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// writeFixture writes the files of a map of relative path to content under dir
+func writeFixture(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestChangedSince(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes map[string]string
+		want    []string
+	}{
+		{name: "nothing changed", want: []string{}},
+		{name: "single repository file", changes: map[string]string{"hub/a.yaml": "displayName: A2\npath: ../servers/a\n"}, want: []string{"a"}},
+		{name: "multi-document file", changes: map[string]string{"hub/b.yaml": "name: b1\ndisplayName: B1\n---\nname: b2\ndisplayName: B2 v2\n"}, want: []string{"b1", "b2"}},
+		{name: "local source path", changes: map[string]string{"servers/a/index.js": "console.log('a2')"}, want: []string{"a"}},
+		{name: "other source", changes: map[string]string{"servers/c/index.js": "console.log('c')", "README.md": "hub"}, want: []string{}},
+		{name: "shared defaults", changes: map[string]string{"hub/_defaults.yaml": "license: Apache-2.0\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFixture(t, dir, map[string]string{
+				"hub/a.yaml":         "displayName: A\npath: ../servers/a\n",
+				"hub/b.yaml":         "name: b1\ndisplayName: B1\n---\nname: b2\ndisplayName: B2\n",
+				"hub/_defaults.yaml": "license: MIT\n",
+				"servers/a/index.js": "console.log('a')",
+			})
+			git(t, dir, "init", "-q")
+			git(t, dir, "add", ".")
+			git(t, dir, "commit", "-q", "-m", "hub")
+			writeFixture(t, dir, tt.changes)
+			git(t, dir, "add", ".")
+			git(t, dir, "commit", "-q", "--allow-empty", "-m", "change")
+
+			// Local paths are relative to the working directory of the import
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chdir(filepath.Join(dir, "hub")); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			h := &Hub{}
+			if err := h.Read("."); err != nil {
+				t.Fatal(err)
+			}
+			got, err := h.ChangedSince("HEAD~1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("got %v, want nil for all repositories", got)
+				}
+				return
+			}
+			if got == nil || !slices.Equal(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangedSinceUnknownRef(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, map[string]string{"a.yaml": "displayName: A\n"})
+	git(t, dir, "init", "-q")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "hub")
+
+	h := &Hub{}
+	if err := h.Read(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.ChangedSince("v9.9.9"); err == nil || !strings.HasPrefix(err.Error(), "git diff since v9.9.9: ") {
+		t.Errorf("err = %v, want the failed diff", err)
+	}
+}
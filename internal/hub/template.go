@@ -0,0 +1,51 @@
+package hub
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the repository metadata available to templated fields, e.g. icon: https://cdn.example.com/{{ .Name }}.svg
+type TemplateData struct {
+	Name        string
+	DisplayName string
+	Integration string
+}
+
+// ExpandTemplates resolves the Go templates of the icon and url fields, the other fields are used as is
+func (r *Repository) ExpandTemplates(name string) error {
+	data := TemplateData{
+		Name:        name,
+		DisplayName: r.DisplayName,
+		Integration: r.Integration,
+	}
+	if data.Integration == "" {
+		data.Integration = name
+	}
+
+	var err error
+	if r.Icon, err = expandTemplate("Icon", r.Icon, data); err != nil {
+		return err
+	}
+	if r.URL, err = expandTemplate("URL", r.URL, data); err != nil {
+		return err
+	}
+	return nil
+}
+
+func expandTemplate(field string, text string, data TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New(field).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template in field %s: %w", field, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to expand template in field %s: %w", field, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,70 @@
+package hub
+
+import "testing"
+
+func TestExpandTemplates(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository Repository
+		wantIcon   string
+		wantURL    string
+		wantErr    bool
+	}{
+		{
+			name:       "icon from the name",
+			repository: Repository{Icon: "https://cdn.example.com/{{ .Name }}.svg"},
+			wantIcon:   "https://cdn.example.com/brave.svg",
+		},
+		{
+			name:       "url from the integration",
+			repository: Repository{Icon: "https://cdn.example.com/logo.svg", URL: "https://docs.example.com/{{ .Integration }}", Integration: "brave-search"},
+			wantIcon:   "https://cdn.example.com/logo.svg",
+			wantURL:    "https://docs.example.com/brave-search",
+		},
+		{
+			name:       "integration defaults to the name",
+			repository: Repository{Icon: "https://cdn.example.com/{{ .Integration }}.svg"},
+			wantIcon:   "https://cdn.example.com/brave.svg",
+		},
+		{
+			name:       "unknown field",
+			repository: Repository{Icon: "https://cdn.example.com/{{ .Owner }}.svg"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid template",
+			repository: Repository{Icon: "https://cdn.example.com/{{ .Name }.svg"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := tt.repository
+			err := repository.ExpandTemplates("brave")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if repository.Icon != tt.wantIcon || repository.URL != tt.wantURL {
+				t.Errorf("icon = %s, url = %s, want %s and %s", repository.Icon, repository.URL, tt.wantIcon, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestExpandTemplatesOtherFields(t *testing.T) {
+	repository := Repository{
+		Icon:            "https://cdn.example.com/logo.svg",
+		Description:     "Render {{ .Name }} templates",
+		PreHooks:        []string{`echo "{{ .Name }}"`},
+		LongDescription: "{{ unclosed",
+	}
+	if err := repository.ExpandTemplates("brave"); err != nil {
+		t.Fatal(err)
+	}
+	if repository.Description != "Render {{ .Name }} templates" || repository.PreHooks[0] != `echo "{{ .Name }}"` {
+		t.Errorf("fields other than icon and url expanded: %+v", repository)
+	}
+}
@@ -0,0 +1,91 @@
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Vocabulary is the controlled list of the tags and categories a repository can use
+type Vocabulary struct {
+	Tags       []string `yaml:"tags"`
+	Categories []string `yaml:"categories"`
+}
+
+// ReadVocabulary reads a vocabulary file listing the allowed tags and categories
+func ReadVocabulary(path string) (*Vocabulary, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var v Vocabulary
+	if err := yaml.UnmarshalStrict(content, &v); err != nil {
+		return nil, fmt.Errorf("parse vocabulary file %s: %w", path, err)
+	}
+	return &v, nil
+}
+
+// ValidateVocabulary checks that the tags and categories of every repository are in the vocabulary, ignoring case
+func (h *Hub) ValidateVocabulary(v *Vocabulary) error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		repository := h.Repositories[name]
+		for _, tag := range repository.Tags {
+			if err := checkVocabulary("tag", tag, v.Tags, name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for _, category := range repository.Categories {
+			if err := checkVocabulary("category", category, v.Categories, name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkVocabulary returns an error suggesting the closest allowed value when value is not allowed
+func checkVocabulary(kind string, value string, allowed []string, repository string) error {
+	suggestion, best := "", -1
+	for _, a := range allowed {
+		d := levenshtein(strings.ToLower(value), strings.ToLower(a))
+		if d == 0 {
+			return nil
+		}
+		if best < 0 || d < best {
+			suggestion, best = a, d
+		}
+	}
+	// Only suggest values close enough to be a typo or a variant
+	if best >= 0 && best <= max(2, len([]rune(value))/3) {
+		return fmt.Errorf("unknown %s %s in repository %s, did you mean %s?", kind, value, repository, suggestion)
+	}
+	return fmt.Errorf("unknown %s %s in repository %s", kind, value, repository)
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
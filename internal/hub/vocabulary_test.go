@@ -0,0 +1,51 @@
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestValidateVocabulary(t *testing.T) {
+	v := &Vocabulary{Tags: []string{"search", "messaging", "storage"}, Categories: []string{"productivity", "developer-tools"}}
+	tests := []struct {
+		name       string
+		repository *Repository
+		want       string
+	}{
+		{name: "valid, ignoring case", repository: &Repository{Tags: []string{"Search"}, Categories: []string{"productivity"}}},
+		{name: "typo suggestion", repository: &Repository{Tags: []string{"serach"}}, want: "unknown tag serach in repository brave, did you mean search?"},
+		{name: "variant suggestion", repository: &Repository{Categories: []string{"developer-tool"}}, want: "unknown category developer-tool in repository brave, did you mean developer-tools?"},
+		{name: "unknown without suggestion", repository: &Repository{Tags: []string{"finance"}}, want: "unknown tag finance in repository brave"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Hub{Repositories: map[string]*Repository{"brave": tt.repository}}
+			if got := errorString(h.ValidateVocabulary(v)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadVocabulary(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vocabulary.yaml")
+	if err := os.WriteFile(path, []byte("tags: [search]\ncategories: [productivity]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := ReadVocabulary(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(v.Tags, []string{"search"}) || !slices.Equal(v.Categories, []string{"productivity"}) {
+		t.Errorf("got %+v", v)
+	}
+	if err := os.WriteFile(path, []byte("tag: [search]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadVocabulary(path); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
@@ -0,0 +1,52 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Write serializes the repositories of the hub as yaml or json, with the yaml field names in both cases
+func (h *Hub) Write(out io.Writer, format string) error {
+	content, err := yaml.Marshal(h)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "yaml":
+		_, err = out.Write(content)
+		return err
+	case "json":
+		// Go through yaml so json uses the same field names
+		var value interface{}
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(jsonValue(value))
+	default:
+		return fmt.Errorf("unknown format %s, expected yaml or json", format)
+	}
+}
+
+// jsonValue converts the maps decoded by yaml, keyed by interface{}, to maps json can encode
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Rule checks a repository and returns a message per problem found
+type Rule struct {
+	Name     string
+	Severity Severity
+	Check    func(repository *hub.Repository) []string
+}
+
+type Issue struct {
+	Repository string
+	Rule       string
+	Severity   Severity
+	Message    string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", i.Severity, i.Repository, i.Message, i.Rule)
+}
+
+var rules []Rule
+
+// Register adds a rule to the rule set run by Lint
+func Register(rule Rule) {
+	rules = append(rules, rule)
+}
+
+// Lint runs every registered rule against the repositories of the hub, sorted by repository name
+func Lint(h *hub.Hub) []Issue {
+	var issues []Issue
+	for _, name := range slices.Sorted(maps.Keys(h.Repositories)) {
+		for _, rule := range rules {
+			for _, message := range rule.Check(h.Repositories[name]) {
+				issues = append(issues, Issue{
+					Repository: name,
+					Rule:       rule.Name,
+					Severity:   rule.Severity,
+					Message:    message,
+				})
+			}
+		}
+	}
+	return issues
+}
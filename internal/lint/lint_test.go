@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+func TestLint(t *testing.T) {
+	h := &hub.Hub{Repositories: map[string]*hub.Repository{
+		"slack": {
+			Description:     "Send messages to Slack.",
+			LongDescription: "Send messages to Slack.",
+			URL:             "https://api.slack.com",
+			OAuth:           &hub.OAuth{},
+		},
+		"brave": {
+			Description:     "Search the web.",
+			LongDescription: "Search the web using Brave's search engine, with its news, images and videos results.",
+			URL:             "https://brave.com/search/api",
+			Tags:            []string{"search"},
+			Categories:      []string{"search"},
+		},
+		"notion": {
+			Description:     "Read Notion pages.",
+			LongDescription: "Read Notion pages.",
+			Tags:            []string{"notes"},
+			Categories:      []string{"productivity"},
+			Disabled:        true,
+		},
+	}}
+	var got []string
+	for _, issue := range Lint(h) {
+		if issue.Severity != SeverityWarning {
+			t.Errorf("%s is not a warning", issue)
+		}
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"warning: notion: longDescription is the same as description (long-description-length)",
+		"warning: slack: longDescription is the same as description (long-description-length)",
+		"warning: slack: no tags (tags)",
+		"warning: slack: no categories (categories)",
+		"warning: slack: oauth connector without scopes (oauth-scopes)",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer func(registered []Rule) { rules = registered }(slices.Clone(rules))
+	Register(Rule{Name: "license", Severity: SeverityError, Check: func(repository *hub.Repository) []string {
+		if repository.License == "" {
+			return []string{"no license"}
+		}
+		return nil
+	}})
+
+	issues := Lint(&hub.Hub{Repositories: map[string]*hub.Repository{"brave": {}}})
+	last := issues[len(issues)-1]
+	if last.String() != "error: brave: no license (license)" {
+		t.Errorf("last issue %q, want the registered rule", last)
+	}
+}
@@ -0,0 +1,55 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/blaxel-ai/mcp-hub/internal/hub"
+)
+
+const minLongDescription = 80
+
+func init() {
+	Register(Rule{Name: "long-description-length", Severity: SeverityWarning, Check: checkLongDescription})
+	Register(Rule{Name: "tags", Severity: SeverityWarning, Check: checkTags})
+	Register(Rule{Name: "categories", Severity: SeverityWarning, Check: checkCategories})
+	Register(Rule{Name: "oauth-scopes", Severity: SeverityWarning, Check: checkOAuthScopes})
+	Register(Rule{Name: "url", Severity: SeverityWarning, Check: checkURL})
+}
+
+func checkLongDescription(repository *hub.Repository) []string {
+	if repository.LongDescription == repository.Description {
+		return []string{"longDescription is the same as description"}
+	}
+	if len([]rune(repository.LongDescription)) < minLongDescription {
+		return []string{fmt.Sprintf("longDescription is shorter than %d characters", minLongDescription)}
+	}
+	return nil
+}
+
+func checkTags(repository *hub.Repository) []string {
+	if len(repository.Tags) == 0 {
+		return []string{"no tags"}
+	}
+	return nil
+}
+
+func checkCategories(repository *hub.Repository) []string {
+	if len(repository.Categories) == 0 {
+		return []string{"no categories"}
+	}
+	return nil
+}
+
+func checkOAuthScopes(repository *hub.Repository) []string {
+	if repository.OAuth != nil && len(repository.OAuth.Scopes) == 0 {
+		return []string{"oauth connector without scopes"}
+	}
+	return nil
+}
+
+func checkURL(repository *hub.Repository) []string {
+	if repository.URL == "" && !repository.Disabled {
+		return []string{"no url to help users set up the connector"}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Package mcpclient is a minimal client of the Model Context Protocol over stdio, enough to call a tool of a server
+package mcpclient
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ProtocolVersion is the MCP revision the client negotiates
+const ProtocolVersion = "2024-11-05"
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type response struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ToolResult is the result of a tools/call request
+type ToolResult struct {
+	Content []json.RawMessage `json:"content"`
+	IsError bool              `json:"isError,omitempty"`
+}
+
+// Client sends newline delimited JSON-RPC messages to a server and reads its responses,
+// the lines that are not responses, such as logs and notifications, are skipped. Requests are sent one at a time.
+type Client struct {
+	w      io.Writer
+	r      *bufio.Scanner
+	nextID int
+}
+
+// NewClient returns a client writing the requests to w and reading the responses from r
+func NewClient(w io.Writer, r io.Reader) *Client {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &Client{w: w, r: scanner}
+}
+
+// Initialize performs the handshake, it must be called before any other request
+func (c *Client) Initialize(clientName string, clientVersion string) error {
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": clientName, "version": clientVersion},
+	}
+	if _, err := c.call("initialize", params); err != nil {
+		return fmt.Errorf("initialize: %w", err)
+	}
+	return c.send(request{JSONRPC: "2.0", Method: "notifications/initialized"})
+}
+
+// CallTool calls a tool of the server with its arguments
+func (c *Client) CallTool(name string, arguments map[string]any) (*ToolResult, error) {
+	result, err := c.call("tools/call", map[string]any{"name": name, "arguments": arguments})
+	if err != nil {
+		return nil, fmt.Errorf("call tool %s: %w", name, err)
+	}
+	var toolResult ToolResult
+	if err := json.Unmarshal(result, &toolResult); err != nil {
+		return nil, fmt.Errorf("call tool %s: invalid result: %w", name, err)
+	}
+	return &toolResult, nil
+}
+
+func (c *Client) call(method string, params any) (json.RawMessage, error) {
+	c.nextID++
+	id := c.nextID
+	if err := c.send(request{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		return nil, err
+	}
+	for c.r.Scan() {
+		var resp response
+		if err := json.Unmarshal(c.r.Bytes(), &resp); err != nil || resp.ID == nil || *resp.ID != id {
+			continue
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return resp.Result, nil
+	}
+	if err := c.r.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("the server closed its output before responding")
+}
+
+func (c *Client) send(req request) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,99 @@
+package mcpclient
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// mockServer answers the requests read from r on w until r is closed, it logs a line before every response
+// as real servers do. The tools/call requests are answered by callTool.
+func mockServer(r io.Reader, w io.WriteCloser, callTool func(params json.RawMessage) string) {
+	defer w.Close()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var req struct {
+			ID     int             `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == 0 {
+			continue
+		}
+		fmt.Fprintln(w, "server log: handling", req.Method)
+		switch req.Method {
+		case "initialize":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"protocolVersion":"2024-11-05","capabilities":{"tools":{}}}}`+"\n", req.ID)
+		case "tools/call":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,%s}`+"\n", req.ID, callTool(req.Params))
+		}
+	}
+}
+
+func TestClientCallTool(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "known result",
+			response: `"result":{"content":[{"type":"text","text":"3 results for mcp"}]}`,
+			want:     `{"type":"text","text":"3 results for mcp"}`,
+		},
+		{
+			name:     "tool error",
+			response: `"result":{"content":[{"type":"text","text":"rate limited"}],"isError":true}`,
+			want:     `{"type":"text","text":"rate limited"}`,
+		},
+		{
+			name:     "unknown tool",
+			response: `"error":{"code":-32602,"message":"Unknown tool: search"}`,
+			wantErr:  "call tool search: error -32602: Unknown tool: search",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestR, requestW := io.Pipe()
+			responseR, responseW := io.Pipe()
+			var params string
+			go mockServer(requestR, responseW, func(p json.RawMessage) string {
+				params = string(p)
+				return tt.response
+			})
+			defer requestW.Close()
+
+			client := NewClient(requestW, responseR)
+			if err := client.Initialize("mcp-hub", "v1"); err != nil {
+				t.Fatal(err)
+			}
+			result, err := client.CallTool("search", map[string]any{"query": "mcp"})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if params != `{"arguments":{"query":"mcp"},"name":"search"}` {
+				t.Errorf("params = %s", params)
+			}
+			if len(result.Content) != 1 || string(result.Content[0]) != tt.want || result.IsError != (tt.name == "tool error") {
+				t.Errorf("got %+v, want %s", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientServerClosed(t *testing.T) {
+	client := NewClient(io.Discard, strings.NewReader("starting server\n"))
+	if err := client.Initialize("mcp-hub", "v1"); err == nil || err.Error() != "initialize: the server closed its output before responding" {
+		t.Errorf("err = %v, want the closed output", err)
+	}
+}
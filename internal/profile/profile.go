@@ -0,0 +1,43 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Profile holds the settings of an environment the hub is pushed to
+type Profile struct {
+	Registry  string   `yaml:"registry"`
+	Tag       string   `yaml:"tag"`
+	Platforms []string `yaml:"platforms"`
+	Push      *bool    `yaml:"push"`
+}
+
+// Read returns the profile name of the profiles file at path, a map of profile names to profiles
+func Read(path string, name string) (*Profile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read profiles file: %w", err)
+	}
+	profiles := make(map[string]*Profile)
+	if err := yaml.UnmarshalStrict(content, &profiles); err != nil {
+		return nil, fmt.Errorf("parse profiles file %s: %w", path, err)
+	}
+	p, ok := profiles[name]
+	if !ok {
+		var names []string
+		for n := range profiles {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("profile %s not found in %s, available profiles: %s", name, path, strings.Join(names, ", "))
+	}
+	if p == nil {
+		p = &Profile{}
+	}
+	return p, nil
+}
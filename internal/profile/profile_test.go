@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	content := "dev:\n  registry: ghcr.io/hub-dev\n  tag: dev\n  push: true\nprod:\n  registry: ghcr.io/hub\n  platforms: [linux/amd64, linux/arm64]\nlocal:\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Read(path, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Registry != "ghcr.io/hub-dev" || p.Tag != "dev" || p.Push == nil || !*p.Push {
+		t.Errorf("got dev profile %+v", p)
+	}
+	p, err = Read(path, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(p.Platforms, []string{"linux/amd64", "linux/arm64"}) || p.Push != nil {
+		t.Errorf("got prod profile %+v", p)
+	}
+	if p, err := Read(path, "local"); err != nil || p == nil {
+		t.Errorf("empty profile = %v, %v", p, err)
+	}
+
+	_, err = Read(path, "staging")
+	if err == nil || err.Error() != "profile staging not found in "+path+", available profiles: dev, local, prod" {
+		t.Errorf("err = %v, want the available profiles", err)
+	}
+	if err := os.WriteFile(path, []byte("dev:\n  registy: ghcr.io/hub-dev\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path, "dev"); err == nil || !strings.Contains(err.Error(), "registy") {
+		t.Errorf("err = %v, want the unknown field", err)
+	}
+}
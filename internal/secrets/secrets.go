@@ -0,0 +1,35 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	ProviderEnv   = "env"
+	ProviderVault = "vault"
+)
+
+// SecretProvider resolves the value of an environment variable required by a MCP
+type SecretProvider interface {
+	Get(key string) (string, error)
+}
+
+// NewSecretProvider returns the provider registered under the given name
+func NewSecretProvider(name string) (SecretProvider, error) {
+	switch name {
+	case "", ProviderEnv:
+		return &EnvProvider{}, nil
+	case ProviderVault:
+		return NewVaultProvider(os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN"), os.Getenv("VAULT_SECRET_PATH"))
+	default:
+		return nil, fmt.Errorf("unsupported secret provider: %s", name)
+	}
+}
+
+// EnvProvider reads secrets from the process environment
+type EnvProvider struct{}
+
+func (p *EnvProvider) Get(key string) (string, error) {
+	return os.Getenv(key), nil
+}
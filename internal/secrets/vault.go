@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VaultProvider reads secrets from a HashiCorp Vault KV v2 secret
+// The whole secret is fetched once and every key is looked up in it
+type VaultProvider struct {
+	Address string
+	Token   string
+	Path    string
+
+	values map[string]string
+}
+
+func NewVaultProvider(address string, token string, path string) (*VaultProvider, error) {
+	if address == "" {
+		return nil, errors.New("VAULT_ADDR is required for the vault secret provider")
+	}
+	if token == "" {
+		return nil, errors.New("VAULT_TOKEN is required for the vault secret provider")
+	}
+	if path == "" {
+		return nil, errors.New("VAULT_SECRET_PATH is required for the vault secret provider")
+	}
+	return &VaultProvider{
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   token,
+		Path:    strings.Trim(path, "/"),
+	}, nil
+}
+
+func (p *VaultProvider) Get(key string) (string, error) {
+	if p.values == nil {
+		if err := p.load(); err != nil {
+			return "", err
+		}
+	}
+	return p.values[key], nil
+}
+
+func (p *VaultProvider) load() error {
+	// secret/foo -> secret/data/foo
+	mount, path, _ := strings.Cut(p.Path, "/")
+	url := fmt.Sprintf("%s/v1/%s/data/%s", p.Address, mount, path)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("failed to read vault secret %s: HTTP %d", p.Path, resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode vault secret %s: %w", p.Path, err)
+	}
+
+	p.values = make(map[string]string)
+	for key, value := range body.Data.Data {
+		p.values[key] = fmt.Sprint(value)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package secrets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVaultProviderGet(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v1/secret/data/hub/brave" {
+			t.Errorf("got path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-Vault-Token") != "token" {
+			t.Errorf("got token %q", r.Header.Get("X-Vault-Token"))
+		}
+		w.Write([]byte(`{"data": {"data": {"API_KEY": "secret", "PORT": 8080}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(server.URL+"/", "token", "/secret/hub/brave/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"API_KEY": "secret", "PORT": "8080", "MISSING": ""} {
+		value, err := provider.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != want {
+			t.Errorf("%s = %q, want %q", key, value, want)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("%d requests, want the secret read once", requests)
+	}
+}
+
+func TestVaultProviderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(server.URL, "token", "secret/hub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Get("API_KEY"); err == nil {
+		t.Error("expected an error for a forbidden secret")
+	}
+	if _, err := NewVaultProvider(server.URL, "", "secret/hub"); err == nil {
+		t.Error("expected an error without a token")
+	}
+}
+
+func TestNewSecretProvider(t *testing.T) {
+	t.Setenv("API_KEY", "from-env")
+	provider, err := NewSecretProvider(ProviderEnv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := provider.Get("API_KEY"); value != "from-env" {
+		t.Errorf("API_KEY = %q, want the environment value", value)
+	}
+	if _, err := NewSecretProvider("keychain"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
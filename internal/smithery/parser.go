@@ -1,6 +1,7 @@
 package smithery
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -8,9 +9,15 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// ErrNotFound is returned by Parse when the smithery file does not exist
+var ErrNotFound = errors.New("smithery file not found")
+
 func Parse(path string) (SmitheryConfig, error) {
 	file, err := os.Open(path)
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return SmitheryConfig{}, fmt.Errorf("%s: %w", path, ErrNotFound)
+		}
 		return SmitheryConfig{}, err
 	}
 
@@ -19,7 +26,7 @@ func Parse(path string) (SmitheryConfig, error) {
 	var smithery SmitheryConfig
 	err = yaml.NewDecoder(file).Decode(&smithery)
 	if err != nil {
-		return SmitheryConfig{}, err
+		return SmitheryConfig{}, fmt.Errorf("malformed smithery file %s: %w", path, err)
 	}
 
 	parsedCommand, err := ExecuteCommandFunction(smithery.StartCommand.CommandFunction, smithery.StartCommand.ConfigSchema.Properties)
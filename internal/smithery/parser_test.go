@@ -0,0 +1,40 @@
+package smithery
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"smithery.yaml":  "startCommand:\n  type: stdio\n  commandFunction: |-\n    config=>({command:'node',args:['index.js'],env:{}})\n",
+		"malformed.yaml": "startCommand:\n  type: [stdio\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := Parse(filepath.Join(dir, "smithery.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ParsedCommand.Command != "node" || cfg.ParsedCommand.Type != "stdio" {
+		t.Errorf("got command %+v", cfg.ParsedCommand)
+	}
+
+	_, err = Parse(filepath.Join(dir, "missing.yaml"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want %v", err, ErrNotFound)
+	}
+
+	_, err = Parse(filepath.Join(dir, "malformed.yaml"))
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.HasPrefix(err.Error(), "malformed smithery file ") {
+		t.Errorf("err = %v, want a malformed file", err)
+	}
+}
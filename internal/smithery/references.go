@@ -0,0 +1,84 @@
+package smithery
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dop251/goja"
+)
+
+// ReferencedProperties returns the config properties read by the command function, declared or not
+func ReferencedProperties(commandFn string, config map[string]Property) ([]string, error) {
+	vm := goja.New()
+
+	target := vm.NewObject()
+	for key, prop := range config {
+		value := prop.Default
+		if value == "" {
+			value = "$" + key
+		}
+		if err := target.Set(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set config: %w", err)
+		}
+	}
+
+	var referenced []string
+	proxy := vm.NewProxy(target, &goja.ProxyTrapConfig{
+		Get: func(target *goja.Object, property string, receiver goja.Value) goja.Value {
+			if !slices.Contains(referenced, property) {
+				referenced = append(referenced, property)
+			}
+			return target.Get(property)
+		},
+	})
+	if err := vm.Set("config", proxy); err != nil {
+		return nil, fmt.Errorf("failed to set config: %w", err)
+	}
+
+	if _, err := vm.RunString(fmt.Sprintf(`(%s)(config)`, commandFn)); err != nil {
+		return nil, fmt.Errorf("failed to execute command function: %w", err)
+	}
+	slices.Sort(referenced)
+	return referenced, nil
+}
+
+// UndeclaredReferences returns a warning for every property read by the command function that is missing
+// from the config schema, and for every declared secret missing from it
+func UndeclaredReferences(cfg *SmitheryConfig, secrets []string) ([]string, error) {
+	properties := cfg.StartCommand.ConfigSchema.Properties
+	var warnings []string
+	if cfg.StartCommand.CommandFunction != "" {
+		referenced, err := ReferencedProperties(cfg.StartCommand.CommandFunction, properties)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range referenced {
+			if _, ok := properties[name]; !ok {
+				warnings = append(warnings, fmt.Sprintf("command function references %s which is not declared in the config schema", name))
+			}
+		}
+	}
+	for _, secret := range secrets {
+		if _, ok := properties[secret]; !ok {
+			warnings = append(warnings, fmt.Sprintf("secret %s is not declared in the config schema", secret))
+		}
+	}
+	return warnings, nil
+}
+
+// UndeclaredRequired returns a warning for every required property without a default value that is not a secret,
+// users are then asked for it in the config form instead of the secrets one
+func UndeclaredRequired(cfg *SmitheryConfig, secrets []string) []string {
+	var warnings []string
+	for _, name := range cfg.StartCommand.ConfigSchema.Required {
+		property, ok := cfg.StartCommand.ConfigSchema.Properties[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("required property %s is not declared in the config schema", name))
+			continue
+		}
+		if property.Default == "" && !slices.Contains(secrets, name) {
+			warnings = append(warnings, fmt.Sprintf("required property %s has no default and is not declared as a secret", name))
+		}
+	}
+	return warnings
+}
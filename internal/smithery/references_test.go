@@ -0,0 +1,47 @@
+package smithery
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUndeclaredReferences(t *testing.T) {
+	cfg := &SmitheryConfig{}
+	cfg.StartCommand.CommandFunction = `config=>({command:'node',args:['dist/index.js'],env:{BRAVE_API_KEY:config.braveApiKey,BRAVE_REGION:config.region}})`
+	cfg.StartCommand.ConfigSchema.Required = []string{"braveApiKey", "country"}
+	cfg.StartCommand.ConfigSchema.Properties = map[string]Property{
+		"braveApiKey": {Type: "string"},
+		"country":     {Type: "string"},
+	}
+
+	warnings, err := UndeclaredReferences(cfg, []string{"braveApiKey", "proxyToken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"command function references region which is not declared in the config schema",
+		"secret proxyToken is not declared in the config schema",
+	}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("got  %q\nwant %q", warnings, want)
+	}
+
+	want = []string{"required property country has no default and is not declared as a secret"}
+	if warnings := UndeclaredRequired(cfg, []string{"braveApiKey"}); !slices.Equal(warnings, want) {
+		t.Errorf("got  %q\nwant %q", warnings, want)
+	}
+}
+
+func TestReferencedProperties(t *testing.T) {
+	properties := map[string]Property{"braveApiKey": {Type: "string"}, "region": {Type: "string", Default: "us"}}
+	referenced, err := ReferencedProperties(`config=>({command:'node',env:{KEY:config.braveApiKey,REGION:config.region,DEBUG:config.debug}})`, properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"braveApiKey", "debug", "region"}; !slices.Equal(referenced, want) {
+		t.Errorf("got %v, want %v", referenced, want)
+	}
+	if _, err := ReferencedProperties(`config=>(`, properties); err == nil {
+		t.Error("expected an error for a command function that doesn't parse")
+	}
+}